@@ -0,0 +1,123 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/godbus/dbus/v5"
+)
+
+// printerBackend 执行打印队列操作的后端
+type printerBackend interface {
+	// AddPrinter 使用本地 PPD 文件创建（或替换）打印队列
+	AddPrinter(q printerQueue, ppdPath string) error
+	// DeletePrinter 删除打印队列
+	DeletePrinter(name string) error
+	// SetDefaultPrinter 设置默认打印机
+	SetDefaultPrinter(name string) error
+	// Name 返回后端名称（用于日志和状态显示）
+	Name() string
+}
+
+// newPrinterBackend 选择打印队列后端
+// 以 root 运行时直接调用 lpadmin；普通用户优先通过特权助手操作，
+// 助手不可用时回退为直接调用（要求用户属于 lpadmin 组）
+func newPrinterBackend() printerBackend {
+	if os.Geteuid() == 0 {
+		return localBackend{}
+	}
+
+	backend, err := newHelperBackend()
+	if err != nil {
+		fmt.Printf("! 特权助手不可用，将直接调用 lpadmin: %v\n", err)
+		return localBackend{}
+	}
+	return backend
+}
+
+// localBackend 直接在当前进程中调用 lpadmin
+type localBackend struct{}
+
+func (localBackend) AddPrinter(q printerQueue, ppdPath string) error {
+	return addPrinterQueue(q, ppdPath)
+}
+
+func (localBackend) DeletePrinter(name string) error {
+	return deletePrinterQueue(name)
+}
+
+func (localBackend) SetDefaultPrinter(name string) error {
+	return setDefaultPrinter(name)
+}
+
+func (localBackend) Name() string {
+	return "lpadmin"
+}
+
+// helperBackend 通过 D-Bus 调用特权助手
+type helperBackend struct {
+	obj dbus.BusObject
+}
+
+// newHelperBackend 连接特权助手，连接失败或助手无法启动时返回错误
+// 设置环境变量 PRINTER_HELPER_BUS=session 可改用会话总线（本地测试用）
+func newHelperBackend() (*helperBackend, error) {
+	conn, err := connectHelperBus(os.Getenv("PRINTER_HELPER_BUS") == "session")
+	if err != nil {
+		return nil, err
+	}
+
+	obj := conn.Object(helperBusName, helperObjectPath)
+	// Ping 同时会触发 D-Bus 按需启动助手
+	if err := obj.Call("org.freedesktop.DBus.Peer.Ping", 0).Err; err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return &helperBackend{obj: obj}, nil
+}
+
+func (b *helperBackend) AddPrinter(q printerQueue, ppdPath string) error {
+	ppd, err := os.ReadFile(ppdPath)
+	if err != nil {
+		return fmt.Errorf(tr("install.error_read_ppd"), err)
+	}
+
+	call := b.obj.Call(helperInterface+".AddPrinter", 0, q.Name, q.URI, q.Info, q.Location, ppd)
+	return helperCallError(call.Err)
+}
+
+func (b *helperBackend) DeletePrinter(name string) error {
+	call := b.obj.Call(helperInterface+".DeletePrinter", 0, name)
+	return helperCallError(call.Err)
+}
+
+// SetDefaultPrinter 默认打印机是用户级设置（lpoptions -d），无需经过特权助手
+func (b *helperBackend) SetDefaultPrinter(name string) error {
+	return setDefaultPrinter(name)
+}
+
+func (b *helperBackend) Name() string {
+	return tr("backend.helper")
+}
+
+// helperCallError 提取 D-Bus 错误中的可读信息
+func helperCallError(err error) error {
+	if err == nil {
+		return nil
+	}
+	if dbusErr, ok := err.(dbus.Error); ok && len(dbusErr.Body) > 0 {
+		if msg, ok := dbusErr.Body[0].(string); ok {
+			return errors.New(msg)
+		}
+	}
+	return err
+}
+
+// connectHelperBus 连接系统总线或会话总线
+func connectHelperBus(session bool) (*dbus.Conn, error) {
+	if session {
+		return dbus.ConnectSessionBus()
+	}
+	return dbus.ConnectSystemBus()
+}
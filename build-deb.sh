@@ -87,6 +87,15 @@ create_deb() {
         ls -la *.png 2>/dev/null || echo "  没有找到 PNG 文件"
     fi
     
+    # 复制特权助手的 D-Bus 和 polkit 配置
+    mkdir -p "${BUILD_DIR}/usr/share/dbus-1/system.d"
+    mkdir -p "${BUILD_DIR}/usr/share/dbus-1/system-services"
+    mkdir -p "${BUILD_DIR}/usr/share/polkit-1/actions"
+    install -m 644 packaging/dbus/com.kylin.printer.Helper.conf "${BUILD_DIR}/usr/share/dbus-1/system.d/"
+    install -m 644 packaging/dbus/com.kylin.printer.Helper.service "${BUILD_DIR}/usr/share/dbus-1/system-services/"
+    install -m 644 packaging/polkit/com.kylin.printer.installer.policy "${BUILD_DIR}/usr/share/polkit-1/actions/"
+    echo "  ✓ 特权助手配置复制完成"
+    
     # 创建桌面快捷方式
     cat > "${BUILD_DIR}/usr/share/applications/printer-installer.desktop" << EOF
 [Desktop Entry]
@@ -119,7 +128,7 @@ Description: ${DESCRIPTION}
   - 支持批量选择和安装打印机
   - 自动下载和配置 PPD 文件
   - 友好的图形界面
-Depends: cups
+Depends: cups, dbus, policykit-1
 EOF
     
     # 创建 postinst 脚本（安装后执行）
@@ -127,6 +136,16 @@ EOF
 #!/bin/bash
 set -e
 
+# 系统级凭据文件 /etc/printer-installer/credentials.json 仅 printer-installer 组可读
+if ! getent group printer-installer >/dev/null; then
+    groupadd --system printer-installer
+fi
+mkdir -p /etc/printer-installer
+if [ -f /etc/printer-installer/credentials.json ]; then
+    chown root:printer-installer /etc/printer-installer/credentials.json
+    chmod 640 /etc/printer-installer/credentials.json
+fi
+
 # 更新桌面数据库
 if [ -x /usr/bin/update-desktop-database ]; then
     update-desktop-database -q /usr/share/applications 2>/dev/null || true
@@ -10,11 +10,20 @@ rm -rf ${APPDIR}/usr/bin
 mkdir -p ${APPDIR}/usr/bin
 mkdir -p ${APPDIR}/usr/share/icons
 
+# 内置备用字体子集（见 fonts/README.md），缺少时程序仍可编译但没有内置中文字体
+if [ ! -f fonts/ui-subset.otf ]; then
+  if [ -f NotoSansSC-Regular.otf ] && command -v pyftsubset >/dev/null 2>&1; then
+    ./subset_font.sh NotoSansSC-Regular.otf
+  else
+    echo "warning: fonts/ui-subset.otf not found, building without embedded fallback font"
+  fi
+fi
+
 echo ">>> Building for linux/amd64"
-GOOS=linux GOARCH=amd64 CGO_ENABLED=1 go build -o ${OUT}/${APP_NAME}-x86 main.go
+GOOS=linux GOARCH=amd64 CGO_ENABLED=1 go build -o ${OUT}/${APP_NAME}-x86 .
 
 echo ">>> Building for linux/arm64"
-CC=aarch64-linux-gnu-gcc CGO_ENABLED=1 GOOS=linux GOARCH=arm64 PKG_CONFIG_PATH=/usr/lib/aarch64-linux-gnu/pkgconfig go build -o ${OUT}/${APP_NAME}-arm64 main.go
+CC=aarch64-linux-gnu-gcc CGO_ENABLED=1 GOOS=linux GOARCH=arm64 PKG_CONFIG_PATH=/usr/lib/aarch64-linux-gnu/pkgconfig go build -o ${OUT}/${APP_NAME}-arm64 .
 
 cp ${OUT}/${APP_NAME}-x86 ${APPDIR}/usr/bin/${APP_NAME}-x86
 cp ${OUT}/${APP_NAME}-arm64 ${APPDIR}/usr/bin/${APP_NAME}-arm64
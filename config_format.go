@@ -0,0 +1,182 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"mime"
+	"net/url"
+	"path"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// 配置文件格式
+const (
+	formatJSON = "json"
+	formatYAML = "yaml"
+	formatTOML = "toml"
+)
+
+// formatFromName 根据文件扩展名判断格式，无法判断时返回空字符串
+func formatFromName(name string) string {
+	if u, err := url.Parse(name); err == nil && u.Scheme != "" {
+		name = u.Path
+	}
+	switch strings.ToLower(path.Ext(name)) {
+	case ".json":
+		return formatJSON
+	case ".yaml", ".yml":
+		return formatYAML
+	case ".toml":
+		return formatTOML
+	}
+	return ""
+}
+
+// formatFromContentType 根据 HTTP Content-Type 判断格式，无法判断时返回空字符串
+func formatFromContentType(contentType string) string {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return ""
+	}
+	switch {
+	case strings.HasSuffix(mediaType, "json"):
+		return formatJSON
+	case strings.HasSuffix(mediaType, "yaml"):
+		return formatYAML
+	case strings.HasSuffix(mediaType, "toml"):
+		return formatTOML
+	}
+	return ""
+}
+
+// detectConfigFormat 判断配置文档格式：扩展名优先，其次 Content-Type，
+// 都无法判断时以 '{' 开头的内容按 JSON 处理，其余按 YAML 处理（YAML 兼容大部分 JSON）
+func detectConfigFormat(source, contentType string, body []byte) string {
+	if format := formatFromName(source); format != "" {
+		return format
+	}
+	if format := formatFromContentType(contentType); format != "" {
+		return format
+	}
+	if bytes.HasPrefix(bytes.TrimSpace(body), []byte("{")) {
+		return formatJSON
+	}
+	return formatYAML
+}
+
+// decodeConfigDocument 将配置文档解析为通用结构（保留未知字段，便于格式转换）
+func decodeConfigDocument(body []byte, format string) (map[string]interface{}, error) {
+	var doc map[string]interface{}
+	switch format {
+	case formatJSON:
+		decoder := json.NewDecoder(bytes.NewReader(body))
+		decoder.UseNumber()
+		if err := decoder.Decode(&doc); err != nil {
+			return nil, err
+		}
+	case formatYAML:
+		if err := yaml.Unmarshal(body, &doc); err != nil {
+			return nil, err
+		}
+	case formatTOML:
+		if err := toml.Unmarshal(body, &doc); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf(tr("error.config_format"), format)
+	}
+
+	if doc == nil {
+		doc = make(map[string]interface{})
+	}
+	return normalizeDocument(doc).(map[string]interface{}), nil
+}
+
+// normalizeDocument 统一不同解析器产生的类型：
+// 键统一为字符串，JSON 数字转为 int64/float64，去掉 null 值（TOML 不支持 null）
+func normalizeDocument(v interface{}) interface{} {
+	switch value := v.(type) {
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(value))
+		for k, item := range value {
+			if item != nil {
+				out[k] = normalizeDocument(item)
+			}
+		}
+		return out
+	case map[interface{}]interface{}:
+		out := make(map[string]interface{}, len(value))
+		for k, item := range value {
+			if item != nil {
+				out[fmt.Sprint(k)] = normalizeDocument(item)
+			}
+		}
+		return out
+	case []interface{}:
+		out := make([]interface{}, 0, len(value))
+		for _, item := range value {
+			if item != nil {
+				out = append(out, normalizeDocument(item))
+			}
+		}
+		return out
+	case []map[string]interface{}:
+		out := make([]interface{}, 0, len(value))
+		for _, item := range value {
+			out = append(out, normalizeDocument(item))
+		}
+		return out
+	case json.Number:
+		if i, err := value.Int64(); err == nil {
+			return i
+		}
+		f, _ := value.Float64()
+		return f
+	}
+	return v
+}
+
+// encodeConfigDocument 将通用结构编码为指定格式
+func encodeConfigDocument(doc map[string]interface{}, format string) ([]byte, error) {
+	switch format {
+	case formatJSON:
+		data, err := json.MarshalIndent(doc, "", "  ")
+		if err != nil {
+			return nil, err
+		}
+		return append(data, '\n'), nil
+	case formatYAML:
+		var buf bytes.Buffer
+		encoder := yaml.NewEncoder(&buf)
+		encoder.SetIndent(2)
+		if err := encoder.Encode(doc); err != nil {
+			return nil, err
+		}
+		encoder.Close()
+		return buf.Bytes(), nil
+	case formatTOML:
+		var buf bytes.Buffer
+		if err := toml.NewEncoder(&buf).Encode(doc); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	}
+	return nil, fmt.Errorf(tr("error.config_format"), format)
+}
+
+// configFromDocument 将通用结构转换为 PrinterConfig
+func configFromDocument(doc map[string]interface{}) (*PrinterConfig, error) {
+	data, err := json.Marshal(doc)
+	if err != nil {
+		return nil, err
+	}
+	var config PrinterConfig
+	if err := json.Unmarshal(data, &config); err != nil {
+		return nil, err
+	}
+	return &config, nil
+}
@@ -0,0 +1,322 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"fyne.io/fyne/v2"
+)
+
+// defaultConfigURL 默认的配置文件地址
+// 凭据只通过 HTTPS 发送，服务器要求认证时须在设置中改为 https:// 地址
+const defaultConfigURL = "http://10.245.93.86/printer/printer-config.json"
+
+// prefConfigSources 配置来源列表（每行一个 URL 或本地文件路径）
+const prefConfigSources = "config_sources"
+
+// loadConfigSourceList 从 Preferences 读取配置来源，未设置时使用默认地址
+func loadConfigSourceList(prefs fyne.Preferences) []string {
+	sources := make([]string, 0)
+	for _, line := range strings.Split(prefs.String(prefConfigSources), "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			sources = append(sources, line)
+		}
+	}
+	if len(sources) == 0 {
+		sources = append(sources, defaultConfigURL)
+	}
+	return sources
+}
+
+// configDocument 读取到的配置文档原始内容
+type configDocument struct {
+	body        []byte
+	contentType string // HTTP Content-Type，本地文件为空
+}
+
+// isRemoteSource 配置来源是否为 http(s) 地址
+func isRemoteSource(source string) bool {
+	return strings.HasPrefix(source, "http://") || strings.HasPrefix(source, "https://")
+}
+
+// readConfigSource 读取单个配置来源的内容，支持 http(s) URL、file:// URL 和本地路径
+func readConfigSource(client *http.Client, source string) (*configDocument, error) {
+	if isRemoteSource(source) {
+		resp, err := client.Get(source)
+		if err != nil {
+			return nil, err
+		}
+		defer resp.Body.Close()
+
+		// 401/403 等错误页面不是配置内容，直接提示而不是报解析错误
+		if err := checkResponse(resp); err != nil {
+			return nil, err
+		}
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return nil, err
+		}
+		return &configDocument{body: body, contentType: resp.Header.Get("Content-Type")}, nil
+	}
+
+	if strings.HasPrefix(source, "file://") {
+		u, err := url.Parse(source)
+		if err != nil {
+			return nil, err
+		}
+		source = u.Path
+	}
+	body, err := os.ReadFile(source)
+	if err != nil {
+		return nil, err
+	}
+	return &configDocument{body: body}, nil
+}
+
+// sourceLabel 返回配置来源的简短名称，用于界面显示
+func sourceLabel(source string) string {
+	if u, err := url.Parse(source); err == nil && u.Host != "" {
+		return u.Host + "/" + path.Base(u.Path)
+	}
+	return filepath.Base(source)
+}
+
+// loadConfigSources 依次加载所有配置来源并合并
+// 部分来源失败时继续加载其余来源，失败信息记入冲突列表；全部失败时返回错误
+func loadConfigSources(client *http.Client, sources []string) (*PrinterConfig, error) {
+	merged := &PrinterConfig{
+		Locations:     make(map[string][]Printer),
+		PrinterModels: make(map[string]PrinterModelInfo),
+		LocationRules: make(map[string]LocationRule),
+	}
+
+	loader := newConfigLoader(client)
+	loaded := 0
+	var lastErr error
+	for _, source := range sources {
+		config, err := loader.load(source, nil)
+		if err != nil {
+			lastErr = err
+			merged.conflicts = append(merged.conflicts, err.Error())
+			continue
+		}
+		merged.merge(config, source, true)
+		loaded++
+	}
+
+	if loaded == 0 {
+		return nil, lastErr
+	}
+	merged.assignQueueNames()
+	return merged, nil
+}
+
+// configLoader 加载配置文档并展开 include
+// 同一次加载中，被多处引用的文档（如公共型号库）只下载一次
+type configLoader struct {
+	client *http.Client
+	cache  map[string]*configDocument
+}
+
+// newConfigLoader 创建配置加载器
+func newConfigLoader(client *http.Client) *configLoader {
+	return &configLoader{client: client, cache: make(map[string]*configDocument)}
+}
+
+// fetch 读取配置文档内容（带缓存）
+func (l *configLoader) fetch(source string) (*configDocument, error) {
+	if doc, ok := l.cache[source]; ok {
+		return doc, nil
+	}
+	doc, err := readConfigSource(l.client, source)
+	if err != nil {
+		return nil, err
+	}
+	l.cache[source] = doc
+	return doc, nil
+}
+
+// load 加载并解析一个配置文档，递归展开其中的 include
+// stack 为当前的引用链，用于检测循环引用；文档自身的定义优先于被引用文档
+func (l *configLoader) load(source string, stack []string) (*PrinterConfig, error) {
+	for _, s := range stack {
+		if s == source {
+			return nil, fmt.Errorf(tr("error.include_cycle"), strings.Join(append(stack, source), " → "))
+		}
+	}
+
+	raw, err := l.fetch(source)
+	if err != nil {
+		return nil, fmt.Errorf(tr("error.config_load"), source, err)
+	}
+
+	// 支持 JSON、YAML、TOML 三种格式
+	format := detectConfigFormat(source, raw.contentType, raw.body)
+	doc, err := decodeConfigDocument(raw.body, format)
+	if err != nil {
+		return nil, fmt.Errorf(tr("error.config_decode"), source, format, err)
+	}
+
+	// 检查版本要求，再把旧格式的配置升级到当前格式
+	if err := checkClientVersion(doc); err != nil {
+		return nil, fmt.Errorf("%s: %w", source, err)
+	}
+	if _, err := migrateConfigDocument(doc); err != nil {
+		return nil, fmt.Errorf("%s: %v", source, err)
+	}
+	config, err := configFromDocument(doc)
+	if err != nil {
+		return nil, fmt.Errorf(tr("error.config_parse"), source, err)
+	}
+	config.normalize()
+
+	// 标记来源
+	for _, printers := range config.Locations {
+		for i := range printers {
+			printers[i].Source = source
+		}
+	}
+	for model, info := range config.PrinterModels {
+		info.source = source
+		config.PrinterModels[model] = info
+	}
+
+	stack = append(stack, source)
+	for _, ref := range config.Include {
+		includeSource, err := resolveInclude(source, ref)
+		if err != nil {
+			return nil, fmt.Errorf(tr("error.include_invalid"), source, err)
+		}
+		included, err := l.load(includeSource, stack)
+		if err != nil {
+			return nil, err
+		}
+		// 文档自己定义的型号覆盖被引用文件中的同名型号，不算冲突
+		config.merge(included, includeSource, false)
+	}
+	return config, nil
+}
+
+// resolveInclude 将 include 中的相对地址解析为相对于当前文档的地址
+// 远程（http/https）配置只能引用 http/https 地址，不能引用 file:// 或本地路径读取本机文件；
+// 本地配置可以引用本地文件和远程地址
+func resolveInclude(base, ref string) (string, error) {
+	ref = strings.TrimSpace(ref)
+	if ref == "" {
+		return "", errors.New(tr("error.include_empty"))
+	}
+
+	refURL, err := url.Parse(ref)
+	if err != nil {
+		return "", err
+	}
+
+	if isRemoteSource(base) {
+		if refURL.Scheme != "" && refURL.Scheme != "http" && refURL.Scheme != "https" {
+			return "", fmt.Errorf(tr("error.include_remote"), ref)
+		}
+		baseURL, err := url.Parse(base)
+		if err != nil {
+			return "", err
+		}
+		// 以 / 开头的地址解析为同一服务器上的路径，而不是本地文件
+		return baseURL.ResolveReference(refURL).String(), nil
+	}
+
+	switch refURL.Scheme {
+	case "":
+	case "http", "https", "file":
+		return ref, nil
+	default:
+		return "", fmt.Errorf(tr("error.include_scheme"), ref)
+	}
+
+	if strings.HasPrefix(base, "file://") {
+		baseURL, err := url.Parse(base)
+		if err != nil {
+			return "", err
+		}
+		return baseURL.ResolveReference(refURL).String(), nil
+	}
+
+	// 本地文件
+	if filepath.IsAbs(ref) {
+		return ref, nil
+	}
+	return filepath.Join(filepath.Dir(base), ref), nil
+}
+
+// merge 合并另一个来源的配置
+// 地点中的打印机拼接（同一地点重名的打印机保留先加载的），型号按名称合并，
+// 同一型号的 ppd_url 不一致时保留先加载的；reportConflicts 为 true（合并顶层来源）时记录冲突
+func (c *PrinterConfig) merge(src *PrinterConfig, source string, reportConflicts bool) {
+	for _, location := range sortedLocations(src) {
+		for _, printer := range src.Locations[location] {
+			if existing := findPrinter(c.Locations[location], printer.Name); existing != nil {
+				c.conflicts = append(c.conflicts, tr("issues.duplicate_printer",
+					location, printer.Name, sourceLabel(existing.Source), sourceLabel(source), sourceLabel(existing.Source)))
+				continue
+			}
+			c.Locations[location] = append(c.Locations[location], printer)
+		}
+	}
+
+	for model, info := range src.PrinterModels {
+		existing, ok := c.PrinterModels[model]
+		if !ok {
+			c.PrinterModels[model] = info
+			continue
+		}
+		if reportConflicts && existing.PPDURL != info.PPDURL {
+			c.conflicts = append(c.conflicts, tr("issues.ppd_conflict",
+				model, sourceLabel(existing.source), sourceLabel(source), existing.PPDURL))
+		}
+	}
+
+	for location, rule := range src.LocationRules {
+		merged := c.LocationRules[location]
+		merged.Subnets = append(merged.Subnets, rule.Subnets...)
+		merged.Hostnames = append(merged.Hostnames, rule.Hostnames...)
+		c.LocationRules[location] = merged
+	}
+
+	c.locationTree = mergeLocationTree(c.locationTree, src.locationTree)
+	c.conflicts = append(c.conflicts, src.conflicts...)
+}
+
+// findPrinter 按名称查找打印机
+func findPrinter(printers []Printer, name string) *Printer {
+	for i := range printers {
+		if printers[i].Name == name {
+			return &printers[i]
+		}
+	}
+	return nil
+}
+
+// mergeLocationTree 按名称逐级合并地点树
+func mergeLocationTree(dst, src []*locationNode) []*locationNode {
+	for _, node := range src {
+		existing := findChild(dst, node.Name)
+		if existing == nil {
+			dst = append(dst, node)
+			continue
+		}
+		existing.HasPrinters = existing.HasPrinters || node.HasPrinters
+		if existing.Label == existing.Name {
+			existing.Label = node.Label
+		}
+		if existing.Description == "" {
+			existing.Description = node.Description
+		}
+		existing.Children = mergeLocationTree(existing.Children, node.Children)
+	}
+	return dst
+}
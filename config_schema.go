@@ -0,0 +1,178 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// appVersion 程序版本，用于检查配置要求的最低程序版本
+const appVersion = "1.0.1"
+
+// configSchemaVersion 当前程序使用的配置格式版本
+//
+//	1: 平铺的 locations（地点名称 → 打印机列表）+ printer_models，未写 version 字段的配置均视为此版本
+//	2: 层级地点统一写在 location_groups 中，locations 仅为兼容保留
+const configSchemaVersion = 2
+
+// configMigrations 配置格式迁移，第 i 项把版本 i+1 的文档升级到版本 i+2
+var configMigrations = []func(doc map[string]interface{}) error{
+	migrateFlatLocations,
+}
+
+// updateRequiredError 配置要求更新版本的程序
+type updateRequiredError struct {
+	reason string
+}
+
+func (e *updateRequiredError) Error() string {
+	return tr("error.update_required", e.reason)
+}
+
+// documentVersion 读取配置文档的格式版本，未设置时为 1
+func documentVersion(doc map[string]interface{}) (int, error) {
+	value, ok := doc["version"]
+	if !ok {
+		return 1, nil
+	}
+	var version int
+	switch v := value.(type) {
+	case int64:
+		version = int(v)
+	case int:
+		version = v
+	case float64:
+		version = int(v)
+		if float64(version) != v {
+			return 0, fmt.Errorf(tr("error.version_integer"), v)
+		}
+	default:
+		return 0, fmt.Errorf(tr("error.version_integer"), v)
+	}
+	if version < 1 {
+		return 0, fmt.Errorf(tr("error.version_invalid"), version)
+	}
+	return version, nil
+}
+
+// checkClientVersion 检查配置要求的格式版本和最低程序版本，本程序不满足时返回 updateRequiredError
+func checkClientVersion(doc map[string]interface{}) error {
+	version, err := documentVersion(doc)
+	if err != nil {
+		return err
+	}
+	if version > configSchemaVersion {
+		return &updateRequiredError{tr("error.schema_too_new", version, configSchemaVersion)}
+	}
+
+	if value, ok := doc["min_client_version"]; ok {
+		required, ok := value.(string)
+		if !ok {
+			return fmt.Errorf(tr("error.min_client_version"), value)
+		}
+		if compareVersions(appVersion, required) < 0 {
+			return &updateRequiredError{tr("error.client_too_old", required, appVersion)}
+		}
+	}
+	return nil
+}
+
+// migrateConfigDocument 把配置文档就地升级到当前格式版本，返回原来的版本
+func migrateConfigDocument(doc map[string]interface{}) (int, error) {
+	version, err := documentVersion(doc)
+	if err != nil {
+		return 0, err
+	}
+	if version > configSchemaVersion {
+		return version, fmt.Errorf(tr("error.migrate_too_new"), version, configSchemaVersion)
+	}
+	for v := version; v < configSchemaVersion; v++ {
+		if err := configMigrations[v-1](doc); err != nil {
+			return version, fmt.Errorf(tr("error.migrate_failed"), v, v+1, err)
+		}
+	}
+	doc["version"] = int64(configSchemaVersion)
+	return version, nil
+}
+
+// migrateFlatLocations 版本 1 → 2：把平铺的 locations 转为 location_groups，
+// 名称中带 " / " 的地点拆分为对应的层级
+func migrateFlatLocations(doc map[string]interface{}) error {
+	value, ok := doc["locations"]
+	if !ok {
+		return nil
+	}
+	locations, ok := value.(map[string]interface{})
+	if !ok {
+		return errors.New(tr("error.locations_object"))
+	}
+
+	var groups []interface{}
+	if existing, ok := doc["location_groups"]; ok {
+		if groups, ok = existing.([]interface{}); !ok {
+			return errors.New(tr("error.location_groups_array"))
+		}
+	}
+
+	names := make([]string, 0, len(locations))
+	for name := range locations {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		// 从最内层开始向外包装：A / B → {name: A, children: [{name: B, printers: [...]}]}
+		parts := strings.Split(name, locationPathSep)
+		group := map[string]interface{}{
+			"name":     strings.TrimSpace(parts[len(parts)-1]),
+			"printers": locations[name],
+		}
+		for i := len(parts) - 2; i >= 0; i-- {
+			group = map[string]interface{}{
+				"name":     strings.TrimSpace(parts[i]),
+				"children": []interface{}{group},
+			}
+		}
+		groups = append(groups, group)
+	}
+
+	doc["location_groups"] = groups
+	delete(doc, "locations")
+	return nil
+}
+
+// compareVersions 比较两个点分版本号（如 1.0.1），忽略前缀 v 和 -rc1 之类的后缀
+func compareVersions(a, b string) int {
+	pa, pb := versionParts(a), versionParts(b)
+	for len(pa) < len(pb) {
+		pa = append(pa, 0)
+	}
+	for len(pb) < len(pa) {
+		pb = append(pb, 0)
+	}
+	for i := range pa {
+		if pa[i] != pb[i] {
+			if pa[i] < pb[i] {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
+
+// versionParts 拆分版本号中的数字部分
+func versionParts(version string) []int {
+	version = strings.TrimPrefix(strings.TrimSpace(version), "v")
+	if i := strings.IndexAny(version, "-+ "); i >= 0 {
+		version = version[:i]
+	}
+	parts := make([]int, 0, 3)
+	for _, field := range strings.Split(version, ".") {
+		n, _ := strconv.Atoi(field)
+		parts = append(parts, n)
+	}
+	return parts
+}
@@ -0,0 +1,338 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// prefReloadInterval 自动刷新配置的间隔（秒），0 表示关闭
+const prefReloadInterval = "reload_interval"
+
+// defaultReloadInterval 默认每 5 分钟检查一次配置
+const defaultReloadInterval = 5 * time.Minute
+
+// reloadIntervalLabels 自动刷新间隔在界面上的显示名称（翻译消息 ID）
+var reloadIntervalLabels = []struct {
+	interval time.Duration
+	label    string
+}{
+	{0, "settings.reload_off"},
+	{time.Minute, "settings.reload_1m"},
+	{5 * time.Minute, "settings.reload_5m"},
+	{15 * time.Minute, "settings.reload_15m"},
+	{time.Hour, "settings.reload_1h"},
+}
+
+// maxBannerNames 变更提示中每一类最多列出的打印机数量
+const maxBannerNames = 5
+
+// loadReloadInterval 从 Preferences 读取自动刷新间隔
+func loadReloadInterval(prefs fyne.Preferences) time.Duration {
+	seconds := prefs.IntWithFallback(prefReloadInterval, int(defaultReloadInterval/time.Second))
+	if seconds <= 0 {
+		return 0
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+// configChanges 两次加载之间打印机的变化，元素为 "名称 (地点)"
+type configChanges struct {
+	Added   []string
+	Removed []string
+	Changed []string
+}
+
+// Empty 是否没有任何变化
+func (c configChanges) Empty() bool {
+	return len(c.Added) == 0 && len(c.Removed) == 0 && len(c.Changed) == 0
+}
+
+// Summary 变化概要，如 "新增 2 台、移除 1 台"
+func (c configChanges) Summary() string {
+	parts := make([]string, 0, 3)
+	if len(c.Added) > 0 {
+		parts = append(parts, trn("banner.added", len(c.Added)))
+	}
+	if len(c.Removed) > 0 {
+		parts = append(parts, trn("banner.removed", len(c.Removed)))
+	}
+	if len(c.Changed) > 0 {
+		parts = append(parts, trn("banner.changed", len(c.Changed)))
+	}
+	return strings.Join(parts, tr("list.separator"))
+}
+
+// Details 按类别列出变化的打印机
+func (c configChanges) Details() string {
+	lines := make([]string, 0, 3)
+	for _, group := range []struct {
+		label string
+		names []string
+	}{
+		{tr("banner.added_label"), c.Added},
+		{tr("banner.removed_label"), c.Removed},
+		{tr("banner.changed_label"), c.Changed},
+	} {
+		if len(group.names) == 0 {
+			continue
+		}
+		names := group.names
+		more := ""
+		if len(names) > maxBannerNames {
+			more = trn("banner.more", len(names))
+			names = names[:maxBannerNames]
+		}
+		lines = append(lines, group.label+": "+strings.Join(names, tr("list.separator"))+more)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// diffConfigs 比较两次加载的配置，打印机按 printerKey 对应
+// 打印机字段或其型号的 PPD 地址变化都算作变更
+func diffConfigs(old, new *PrinterConfig) configChanges {
+	var changes configChanges
+	oldRows := configRowMap(old)
+	newRows := configRowMap(new)
+
+	for key, row := range newRows {
+		oldRow, ok := oldRows[key]
+		if !ok {
+			changes.Added = append(changes.Added, rowLabel(new, row))
+			continue
+		}
+		if printerChanged(oldRow.Printer, row.Printer) ||
+			old.PrinterModels[oldRow.Printer.Model].PPDURL != new.PrinterModels[row.Printer.Model].PPDURL {
+			changes.Changed = append(changes.Changed, rowLabel(new, row))
+		}
+	}
+	for key, row := range oldRows {
+		if _, ok := newRows[key]; !ok {
+			changes.Removed = append(changes.Removed, rowLabel(old, row))
+		}
+	}
+
+	sort.Strings(changes.Added)
+	sort.Strings(changes.Removed)
+	sort.Strings(changes.Changed)
+	return changes
+}
+
+// configRowMap 返回配置中全部打印机，键为 printerKey
+func configRowMap(config *PrinterConfig) map[string]PrinterRow {
+	rows := make(map[string]PrinterRow)
+	if config == nil {
+		return rows
+	}
+	for location, printers := range config.Locations {
+		for _, printer := range printers {
+			rows[printerKey(location, printer)] = PrinterRow{Location: location, Printer: printer}
+		}
+	}
+	return rows
+}
+
+// printerChanged 比较打印机配置（忽略所属来源）
+func printerChanged(a, b Printer) bool {
+	a.Source, b.Source = "", ""
+	return !reflect.DeepEqual(a, b)
+}
+
+// rowLabel 变更提示中的打印机名称（当前界面语言）
+func rowLabel(config *PrinterConfig, row PrinterRow) string {
+	return fmt.Sprintf("%s (%s)", row.Printer.displayName(), config.locationLabel(row.Location))
+}
+
+// newChangeBanner 创建配置变更提示条（默认隐藏）
+func (gui *PrinterInstallerGUI) newChangeBanner() fyne.CanvasObject {
+	gui.bannerTitle = widget.NewLabel("")
+	gui.bannerTitle.TextStyle = fyne.TextStyle{Bold: true}
+	gui.bannerDetails = widget.NewLabel("")
+	gui.bannerDetails.Wrapping = fyne.TextWrapWord
+
+	closeBtn := widget.NewButtonWithIcon("", theme.CancelIcon(), func() {
+		gui.changeBanner.Hide()
+	})
+	closeBtn.Importance = widget.LowImportance
+
+	gui.changeBanner = container.NewBorder(
+		nil, nil,
+		widget.NewIcon(theme.InfoIcon()),
+		closeBtn,
+		container.NewVBox(gui.bannerTitle, gui.bannerDetails),
+	)
+	gui.changeBanner.Hide()
+	return gui.changeBanner
+}
+
+// showChanges 在提示条中显示配置变化（不打断当前操作）
+func (gui *PrinterInstallerGUI) showChanges(changes configChanges) {
+	gui.bannerTitle.SetText(tr("banner.title", time.Now().Format("15:04"), changes.Summary()))
+	gui.bannerDetails.SetText(changes.Details())
+	gui.changeBanner.Show()
+}
+
+// showCredentialWarnings 在提示条中显示凭据文件无法读取等问题，每次运行只提示一次
+func (gui *PrinterInstallerGUI) showCredentialWarnings(client *http.Client) {
+	warnings := credentialWarnings(client)
+	gui.mutex.Lock()
+	if len(warnings) == 0 || gui.credentialsWarned {
+		gui.mutex.Unlock()
+		return
+	}
+	gui.credentialsWarned = true
+	gui.mutex.Unlock()
+
+	gui.bannerTitle.SetText(tr("credentials.warning_title"))
+	gui.bannerDetails.SetText(strings.Join(warnings, "\n"))
+	gui.changeBanner.Show()
+}
+
+// watchConfig 按设置的间隔在后台检查配置更新
+// 间隔在每轮重新读取，修改设置后无需重启；安装过程中跳过本轮
+func (gui *PrinterInstallerGUI) watchConfig() {
+	for {
+		interval := loadReloadInterval(gui.app.Preferences())
+		if interval <= 0 {
+			// 已关闭自动刷新，稍后再检查设置是否变化
+			time.Sleep(time.Minute)
+			continue
+		}
+		time.Sleep(interval)
+		gui.pollConfig()
+	}
+}
+
+// pollConfig 后台重新加载配置，失败时只更新状态栏，不弹出对话框；正在安装或加载时跳过本轮
+func (gui *PrinterInstallerGUI) pollConfig() {
+	client, err := gui.client()
+	if err != nil {
+		return
+	}
+
+	sources, ok := gui.beginLoad()
+	if !ok {
+		return
+	}
+	defer gui.endLoad()
+
+	config, err := loadConfigSources(client, sources)
+	if err != nil {
+		fmt.Printf("⚠ 自动刷新配置失败: %v\n", err)
+		gui.statusText.Set(tr("status.reload_failed"))
+		return
+	}
+	gui.applyConfig(config)
+}
+
+// applyConfig 使用新加载的配置
+// 首次加载时选择默认地点；之后保留当前地点、勾选和默认打印机（仍存在时），并提示变化
+// 打印机没有任何变化时返回 false
+func (gui *PrinterInstallerGUI) applyConfig(config *PrinterConfig) bool {
+	gui.updateFontCoverage(config)
+
+	gui.mutex.Lock()
+	old := gui.config
+	location := gui.location
+	gui.mutex.Unlock()
+
+	if old == nil || findLocationPath(config.locationTree, location) == nil || len(config.Locations[location]) == 0 {
+		gui.mutex.Lock()
+		gui.config = config
+		gui.mutex.Unlock()
+		gui.updateLocations()
+		if old != nil {
+			if changes := diffConfigs(old, config); !changes.Empty() {
+				gui.showChanges(changes)
+			}
+		}
+		return true
+	}
+
+	changes := diffConfigs(old, config)
+	if changes.Empty() {
+		// 打印机没有变化：不刷新列表，保留选中行、详情和状态栏中的安装结果；
+		// 只有地点名称或描述变化时才重建地点选择框
+		gui.mutex.Lock()
+		gui.config = config
+		gui.mutex.Unlock()
+		if !reflect.DeepEqual(old.locationTree, config.locationTree) {
+			gui.restoreLocationPicker(config, location)
+		}
+		return false
+	}
+
+	// 只保留新配置中仍存在的勾选
+	rows := configRowMap(config)
+	gui.mutex.Lock()
+	gui.config = config
+	checked := make(map[string]bool)
+	for key, ok := range gui.checkedItems {
+		if _, exists := rows[key]; ok && exists {
+			checked[key] = true
+		}
+	}
+	gui.checkedItems = checked
+	if _, exists := rows[gui.defaultKey]; !exists {
+		gui.defaultKey = ""
+	}
+	gui.mutex.Unlock()
+
+	gui.restoreLocationPicker(config, location)
+	gui.applyFilter()
+	gui.showChanges(changes)
+	gui.statusText.Set(tr("status.config_updated", changes.Summary()))
+	return true
+}
+
+// restoreLocationPicker 重建地点选择框后恢复原来的地点，不触发 onLocationChanged 中的重置
+func (gui *PrinterInstallerGUI) restoreLocationPicker(config *PrinterConfig, location string) {
+	gui.mutex.Lock()
+	gui.restoringLocation = true
+	gui.mutex.Unlock()
+
+	gui.locationPicker.SetTree(config.locationTree)
+	gui.locationPicker.SetSelected(location)
+
+	gui.mutex.Lock()
+	gui.restoringLocation = false
+	gui.mutex.Unlock()
+}
+
+// beginLoad 开始加载配置，返回配置来源；正在安装或已在加载时返回 false
+// 加载和安装互斥：安装使用开始时的配置，加载完成前不会开始新的安装
+func (gui *PrinterInstallerGUI) beginLoad() ([]string, bool) {
+	gui.mutex.Lock()
+	defer gui.mutex.Unlock()
+	if gui.installing || gui.loading {
+		return nil, false
+	}
+	gui.loading = true
+	return gui.configSources, true
+}
+
+// endLoad 加载（含应用新配置）结束
+func (gui *PrinterInstallerGUI) endLoad() {
+	gui.mutex.Lock()
+	gui.loading = false
+	gui.mutex.Unlock()
+}
+
+// beginInstall 开始安装，返回当前配置和默认打印机；正在加载配置或已在安装时返回 false
+func (gui *PrinterInstallerGUI) beginInstall() (*PrinterConfig, string, bool) {
+	gui.mutex.Lock()
+	defer gui.mutex.Unlock()
+	if gui.installing || gui.loading {
+		return nil, "", false
+	}
+	gui.installing = true
+	return gui.config, gui.defaultKey, true
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+)
+
+// runConvertConfig 转换配置文件格式
+// 用法: printer-installer convert-config [-to json|yaml|toml] [-migrate] <输入文件或URL> [输出文件]
+// 未指定 -to 时按输出文件扩展名判断；未指定输出文件时写到标准输出
+func runConvertConfig(args []string) int {
+	flags := flag.NewFlagSet("convert-config", flag.ContinueOnError)
+	to := flags.String("to", "", "输出格式: json, yaml, toml")
+	from := flags.String("from", "", "输入格式（默认按扩展名或内容判断）")
+	migrate := flags.Bool("migrate", false, "同时升级到当前配置格式版本")
+	flags.Usage = func() {
+		fmt.Fprintln(os.Stderr, "用法: printer-installer convert-config [-from 格式] [-to 格式] [-migrate] <输入文件或URL> [输出文件]")
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if flags.NArg() < 1 || flags.NArg() > 2 {
+		flags.Usage()
+		return 2
+	}
+
+	input := flags.Arg(0)
+	output := flags.Arg(1)
+
+	outFormat := *to
+	if outFormat == "" && output != "" {
+		outFormat = formatFromName(output)
+	}
+	if outFormat == "" {
+		fmt.Fprintln(os.Stderr, "无法判断输出格式，请使用 -to 指定")
+		return 2
+	}
+
+	client, err := newHTTPClient(NetworkSettings{})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "网络设置错误: %v\n", err)
+		return 1
+	}
+	raw, err := readConfigSource(client, input)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "读取配置失败: %v\n", err)
+		return 1
+	}
+
+	inFormat := *from
+	if inFormat == "" {
+		inFormat = detectConfigFormat(input, raw.contentType, raw.body)
+	}
+	doc, err := decodeConfigDocument(raw.body, inFormat)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "解析配置失败 (%s): %v\n", inFormat, err)
+		return 1
+	}
+
+	if *migrate {
+		version, err := migrateConfigDocument(doc)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		if version != configSchemaVersion {
+			fmt.Fprintf(os.Stderr, "配置格式已从版本 %d 升级到 %d\n", version, configSchemaVersion)
+		}
+	}
+
+	// 确认内容符合配置结构，避免把错误的文件转换后发布出去
+	if _, err := configFromDocument(doc); err != nil {
+		fmt.Fprintf(os.Stderr, "配置结构错误: %v\n", err)
+		return 1
+	}
+
+	data, err := encodeConfigDocument(doc, outFormat)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "转换失败: %v\n", err)
+		return 1
+	}
+
+	if output == "" {
+		os.Stdout.Write(data)
+		return 0
+	}
+	if err := os.WriteFile(output, data, 0644); err != nil {
+		fmt.Fprintf(os.Stderr, "写入失败: %v\n", err)
+		return 1
+	}
+	fmt.Fprintf(os.Stderr, "已转换: %s (%s) → %s (%s)\n", input, inFormat, output, outFormat)
+	return 0
+}
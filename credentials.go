@@ -0,0 +1,306 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// systemCredentialsFile 系统级凭据文件
+// 图形界面以普通用户身份运行，文件应为 root:printer-installer 0640，
+// 只有加入 credentialsGroup 组的用户能读取
+const systemCredentialsFile = "/etc/printer-installer/credentials.json"
+
+// credentialsGroup 可读取系统级凭据文件的用户组（由安装包创建）
+const credentialsGroup = "printer-installer"
+
+// 凭据类型
+const (
+	credentialBasic     = "basic"      // HTTP Basic 认证
+	credentialBearer    = "bearer"     // Bearer Token
+	credentialTokenFile = "token_file" // 每次请求时从文件读取 Bearer Token（由其他程序定期刷新）
+)
+
+// Credential 访问某个主机使用的凭据
+type Credential struct {
+	Type      string `json:"type"`
+	Username  string `json:"username"`
+	Password  string `json:"password"`
+	Token     string `json:"token"`
+	TokenFile string `json:"token_file"`
+}
+
+// credentialsFile 凭据文件格式，hosts 的键为主机名或 主机名:端口
+type credentialsFile struct {
+	Hosts map[string]Credential `json:"hosts"`
+}
+
+// credentialStore 按主机查找凭据：先查凭据文件，再查系统密钥环
+type credentialStore struct {
+	hosts    map[string]Credential
+	mutex    sync.Mutex
+	keyring  map[string]*Credential // 密钥环查询结果缓存（nil 表示未找到）
+	warnings []string               // 存在但无法使用的凭据文件（如普通用户无权读取的系统文件）
+}
+
+// credentialsPaths 返回凭据文件的查找路径
+// 可用环境变量 PRINTER_INSTALLER_CREDENTIALS 指定，否则依次使用系统级和用户级文件
+func credentialsPaths() []string {
+	if path := os.Getenv("PRINTER_INSTALLER_CREDENTIALS"); path != "" {
+		return []string{path}
+	}
+	paths := []string{systemCredentialsFile}
+	if dir, err := os.UserConfigDir(); err == nil {
+		paths = append(paths, filepath.Join(dir, "printer-installer", "credentials.json"))
+	}
+	return paths
+}
+
+// loadCredentials 读取凭据文件；同一主机在多个文件中出现时以先读到的为准
+func loadCredentials() (*credentialStore, error) {
+	store := &credentialStore{
+		hosts:   make(map[string]Credential),
+		keyring: make(map[string]*Credential),
+	}
+
+	for _, path := range credentialsPaths() {
+		file, err := readCredentialsFile(path)
+		if os.IsPermission(err) {
+			// 当前用户不在 credentialsGroup 组中；不影响其他凭据，但要告诉用户
+			warning := tr("credentials.unreadable", path, credentialsGroup)
+			fmt.Println("⚠ " + warning)
+			store.warnings = append(store.warnings, warning)
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		if file == nil {
+			continue
+		}
+		for host, cred := range file.Hosts {
+			host = strings.ToLower(host)
+			if _, ok := store.hosts[host]; !ok {
+				store.hosts[host] = cred
+			}
+		}
+	}
+	return store, nil
+}
+
+// readCredentialsFile 读取单个凭据文件
+// 文件不存在时返回 nil；当前用户无权读取时返回权限错误（os.IsPermission）；
+// 文件可被其他用户读取时拒绝使用（系统级文件允许组可读）
+func readCredentialsFile(path string) (*credentialsFile, error) {
+	var data []byte
+	var err error
+	if path == systemCredentialsFile {
+		data, err = readProtectedFile(path, 0o040, "error.file_mode_group")
+	} else {
+		data, err = readPrivateFile(path)
+	}
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var file credentialsFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf(tr("error.credentials_parse"), path, err)
+	}
+	for host, cred := range file.Hosts {
+		if err := cred.validate(); err != nil {
+			return nil, fmt.Errorf(tr("error.credentials_invalid"), path, host, err)
+		}
+	}
+	return &file, nil
+}
+
+// readPrivateFile 读取仅所有者可访问的文件（权限不能包含组和其他用户位）
+func readPrivateFile(path string) ([]byte, error) {
+	return readProtectedFile(path, 0, "error.file_mode")
+}
+
+// readProtectedFile 读取文件，权限中除所有者位和 allowed 之外不能有其他位；
+// 权限过宽时返回 modeKey 对应的错误
+func readProtectedFile(path string, allowed os.FileMode, modeKey string) ([]byte, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if info.Mode().Perm()&0o077&^allowed != 0 {
+		return nil, fmt.Errorf(tr(modeKey), path, info.Mode().Perm())
+	}
+	return os.ReadFile(path)
+}
+
+// validate 检查凭据字段是否完整
+func (c Credential) validate() error {
+	switch c.Type {
+	case credentialBasic:
+		if c.Username == "" {
+			return errors.New(tr("error.credentials_no_username"))
+		}
+	case credentialBearer:
+		if c.Token == "" {
+			return errors.New(tr("error.credentials_no_token"))
+		}
+	case credentialTokenFile:
+		if c.TokenFile == "" {
+			return errors.New(tr("error.credentials_no_token_file"))
+		}
+	default:
+		return fmt.Errorf(tr("error.credentials_type"), c.Type)
+	}
+	return nil
+}
+
+// apply 在请求上设置 Authorization 头
+func (c Credential) apply(req *http.Request) error {
+	switch c.Type {
+	case credentialBasic:
+		req.SetBasicAuth(c.Username, c.Password)
+	case credentialBearer:
+		req.Header.Set("Authorization", "Bearer "+c.Token)
+	case credentialTokenFile:
+		token, err := readPrivateFile(c.TokenFile)
+		if err != nil {
+			return fmt.Errorf(tr("error.token_file"), err)
+		}
+		req.Header.Set("Authorization", "Bearer "+strings.TrimSpace(string(token)))
+	}
+	return nil
+}
+
+// lookup 查找主机对应的凭据，先按 主机:端口 再按主机名匹配
+func (s *credentialStore) lookup(hostPort, hostname string) *Credential {
+	for _, key := range []string{strings.ToLower(hostPort), strings.ToLower(hostname)} {
+		if cred, ok := s.hosts[key]; ok {
+			return &cred
+		}
+	}
+	return s.lookupKeyring(strings.ToLower(hostname))
+}
+
+// lookupKeyring 通过 secret-tool 在系统密钥环中查找凭据
+// 条目属性: service=printer-installer host=<主机名> type=bearer|basic；
+// bearer 的密码为令牌，basic 的密码为 "用户名:密码"
+func (s *credentialStore) lookupKeyring(hostname string) *Credential {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if cred, ok := s.keyring[hostname]; ok {
+		return cred
+	}
+
+	var found *Credential
+	if _, err := exec.LookPath("secret-tool"); err == nil {
+		if secret := secretToolLookup(hostname, credentialBearer); secret != "" {
+			found = &Credential{Type: credentialBearer, Token: secret}
+		} else if secret := secretToolLookup(hostname, credentialBasic); secret != "" {
+			if user, pass, ok := strings.Cut(secret, ":"); ok {
+				found = &Credential{Type: credentialBasic, Username: user, Password: pass}
+			}
+		}
+	}
+	s.keyring[hostname] = found
+	return found
+}
+
+// secretToolLookup 查询密钥环条目，未找到时返回空字符串
+func secretToolLookup(hostname, credType string) string {
+	output, err := exec.Command("secret-tool", "lookup",
+		"service", "printer-installer", "host", hostname, "type", credType).Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(output))
+}
+
+// authTransport 按请求的主机自动附加凭据
+// 凭据只通过 HTTPS 发送，需要认证的配置和 PPD 地址必须使用 https://
+type authTransport struct {
+	base  http.RoundTripper
+	store *credentialStore
+
+	mutex  sync.Mutex
+	warned map[string]bool // 已提示过“不通过明文 HTTP 发送凭据”的主机
+}
+
+func (t *authTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	cred := t.store.lookup(req.URL.Host, req.URL.Hostname())
+	if cred == nil || req.Header.Get("Authorization") != "" {
+		return t.base.RoundTrip(req)
+	}
+
+	// 凭据只通过 HTTPS 发送；明文 HTTP 请求（包括 HTTPS 重定向到 HTTP）不附加凭据
+	if req.URL.Scheme != "https" {
+		t.warnPlainHTTP(req.URL)
+		return t.base.RoundTrip(req)
+	}
+
+	// RoundTripper 不能修改原始请求
+	authReq := req.Clone(req.Context())
+	if err := cred.apply(authReq); err != nil {
+		return nil, err
+	}
+	return t.base.RoundTrip(authReq)
+}
+
+// warnPlainHTTP 每个主机只提示一次明文 HTTP 不发送凭据（后台刷新会反复请求同一地址）
+func (t *authTransport) warnPlainHTTP(u *url.URL) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	if t.warned[u.Host] {
+		return
+	}
+	if t.warned == nil {
+		t.warned = make(map[string]bool)
+	}
+	t.warned[u.Host] = true
+	fmt.Printf("⚠ 不通过明文 HTTP 发送凭据，请改用 https: %s\n", u.Redacted())
+}
+
+// credentialWarnings 返回客户端加载凭据时遇到的问题（无法读取的凭据文件）
+func credentialWarnings(client *http.Client) []string {
+	if client == nil {
+		return nil
+	}
+	if t, ok := client.Transport.(*authTransport); ok && t.store != nil {
+		return append([]string(nil), t.store.warnings...)
+	}
+	return nil
+}
+
+// httpStatusError 服务器返回非 200 状态码
+type httpStatusError struct {
+	URL        string
+	StatusCode int
+}
+
+func (e *httpStatusError) Error() string {
+	switch e.StatusCode {
+	case http.StatusUnauthorized:
+		return tr("error.http_401", e.URL)
+	case http.StatusForbidden:
+		return tr("error.http_403", e.URL)
+	}
+	return tr("error.http_status", e.StatusCode, e.URL)
+}
+
+// checkResponse 检查 HTTP 状态码，非 200 时返回 httpStatusError
+func checkResponse(resp *http.Response) error {
+	if resp.StatusCode != http.StatusOK {
+		return &httpStatusError{URL: resp.Request.URL.Redacted(), StatusCode: resp.StatusCode}
+	}
+	return nil
+}
@@ -0,0 +1,117 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// printerQueue 创建 CUPS 打印队列所需的参数
+type printerQueue struct {
+	Name     string // 队列名称（lpadmin -p）
+	URI      string // 设备 URI（lpadmin -v）
+	Info     string // 描述，写入 printer-info（lpadmin -D）
+	Location string // 位置，写入 printer-location（lpadmin -L）
+}
+
+// newPrinterQueue 根据配置中的打印机生成队列参数
+// 未配置 URI 时使用 IPP Everywhere 默认地址
+func newPrinterQueue(row PrinterRow) printerQueue {
+	printer := row.Printer
+	printerURI := printer.URI
+	if printerURI == "" {
+		printerURI = fmt.Sprintf("ipp://%s/ipp/print", printer.IP)
+	}
+	return printerQueue{
+		Name:     printer.queueName(),
+		URI:      printerURI,
+		Info:     printer.cupsInfo(),
+		Location: printer.cupsLocation(row.Location),
+	}
+}
+
+// lpadminArgs 返回创建队列的 lpadmin 参数
+func (q printerQueue) lpadminArgs(ppdPath string) []string {
+	return []string{
+		"-p", q.Name,
+		"-v", q.URI,
+		"-P", ppdPath,
+		"-E",
+		"-D", q.Info,
+		"-L", q.Location,
+	}
+}
+
+// addPrinterQueue 创建打印队列，同名队列已存在时先删除
+func addPrinterQueue(q printerQueue, ppdPath string) error {
+	// 检查打印机是否已存在
+	checkCmd := exec.Command("lpstat", "-p", q.Name)
+	if err := checkCmd.Run(); err == nil {
+		// 打印机已存在，先删除
+		deletePrinterQueue(q.Name)
+	}
+
+	output, err := exec.Command("lpadmin", q.lpadminArgs(ppdPath)...).CombinedOutput()
+	if err != nil {
+		return commandError(output, err)
+	}
+	return nil
+}
+
+// deletePrinterQueue 删除打印队列
+func deletePrinterQueue(name string) error {
+	output, err := exec.Command("lpadmin", "-x", name).CombinedOutput()
+	if err != nil {
+		return commandError(output, err)
+	}
+	return nil
+}
+
+// queueDeviceURI 返回已有打印队列的设备 URI，队列不存在时返回 false
+func queueDeviceURI(name string) (string, bool) {
+	cmd := exec.Command("lpstat", "-v", name)
+	cmd.Env = append(os.Environ(), "LC_ALL=C") // 输出格式固定为 "device for NAME: URI"
+	output, err := cmd.Output()
+	if err != nil {
+		return "", false
+	}
+	prefix := "device for " + name + ": "
+	for _, line := range strings.Split(string(output), "\n") {
+		if strings.HasPrefix(line, prefix) {
+			return strings.TrimSpace(strings.TrimPrefix(line, prefix)), true
+		}
+	}
+	return "", false
+}
+
+// setDefaultPrinter 设置默认打印机
+// 以 root 运行时通过 lpadmin -d 设置系统默认打印机，
+// 普通用户通过 lpoptions -d 设置当前用户的默认打印机（无需额外权限）
+func setDefaultPrinter(name string) error {
+	var cmd *exec.Cmd
+	if os.Geteuid() == 0 {
+		cmd = exec.Command("lpadmin", "-d", name)
+	} else {
+		cmd = exec.Command("lpoptions", "-d", name)
+	}
+
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return commandError(output, err)
+	}
+	return nil
+}
+
+// commandError 将命令输出包装为错误，输出为空时使用原始错误
+func commandError(output []byte, err error) error {
+	msg := strings.TrimSpace(string(output))
+	if msg == "" {
+		if err == nil {
+			return errors.New(tr("error.unknown"))
+		}
+		return fmt.Errorf(tr("error.unknown_cause"), err)
+	}
+	return errors.New(msg)
+}
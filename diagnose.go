@@ -0,0 +1,90 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+// runDiagnose 诊断子命令
+// 用法: printer-installer diagnose fonts [配置文件或URL]
+// 指定配置时同时检查其中打印机、地点名称的字符覆盖
+func runDiagnose(args []string) int {
+	if len(args) < 1 || len(args) > 2 || args[0] != "fonts" {
+		fmt.Fprintln(os.Stderr, "用法: printer-installer diagnose fonts [配置文件或URL]")
+		return 2
+	}
+
+	sample := ""
+	if len(args) == 2 {
+		client, err := newHTTPClient(NetworkSettings{})
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "网络设置错误: %v\n", err)
+			return 1
+		}
+		config, err := loadConfigSources(client, args[1:])
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		sample = configSampleText(config)
+	}
+
+	report, _, _ := findFont(sample)
+	fmt.Print(report.String())
+	if !report.OK() {
+		return 1
+	}
+	return 0
+}
+
+// diagnosticsText 诊断对话框中的文本（可复制给管理员）
+func (gui *PrinterInstallerGUI) diagnosticsText() string {
+	var b strings.Builder
+	fmt.Fprintln(&b, tr("about.version", appVersion))
+	fmt.Fprintln(&b, tr("about.schema_version", configSchemaVersion))
+	fmt.Fprintln(&b, tr("about.backend", gui.backend.Name()))
+	fmt.Fprintln(&b, tr("about.language", currentLocale))
+	fmt.Fprintln(&b, tr("about.sources"))
+	for _, source := range gui.configSources {
+		fmt.Fprintf(&b, "  %s\n", source)
+	}
+	if gui.theme != nil && gui.theme.report != nil {
+		b.WriteString("\n")
+		b.WriteString(gui.theme.report.String())
+	}
+	return b.String()
+}
+
+// showAbout 显示关于/诊断对话框
+func (gui *PrinterInstallerGUI) showAbout() {
+	text := gui.diagnosticsText()
+
+	report := widget.NewLabel(text)
+	report.TextStyle = fyne.TextStyle{Monospace: true}
+	report.Wrapping = fyne.TextWrapWord
+
+	scroll := container.NewVScroll(report)
+	scroll.SetMinSize(fyne.NewSize(560, 320))
+
+	copyBtn := widget.NewButton(tr("about.copy"), func() {
+		gui.window.Clipboard().SetContent(text)
+		gui.statusText.Set(tr("about.copied"))
+	})
+
+	content := container.NewBorder(
+		container.NewVBox(
+			widget.NewLabel(tr("app.title", appVersion)),
+			widget.NewLabel(tr("about.shortcuts")),
+		),
+		container.NewHBox(copyBtn),
+		nil, nil,
+		scroll,
+	)
+	dialog.ShowCustom(tr("about.title"), tr("button.close"), content, gui.window)
+}
@@ -0,0 +1,20 @@
+package main
+
+import "embed"
+
+// embeddedFonts 内置备用字体（构建前由 subset_font.sh 生成，见 fonts/README.md）
+//
+//go:embed fonts
+var embeddedFonts embed.FS
+
+// embeddedFontPath 内置字体子集在 embeddedFonts 中的路径
+const embeddedFontPath = "fonts/ui-subset.otf"
+
+// embeddedFont 返回内置字体数据，构建时未生成则返回 nil
+func embeddedFont() []byte {
+	data, err := embeddedFonts.ReadFile(embeddedFontPath)
+	if err != nil || len(data) == 0 {
+		return nil
+	}
+	return data
+}
@@ -0,0 +1,445 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"sort"
+	"strings"
+	"unicode"
+
+	"golang.org/x/image/font/sfnt"
+)
+
+// 字体来源（按查找顺序），值为翻译消息 ID，显示时经 tr 翻译
+const (
+	fontSourceEnv     = "font.source_env"
+	fontSourceKaiti   = "font.source_kaiti"
+	fontSourceFcList  = "font.source_fc_list"
+	fontSourceStatic  = "font.source_static"
+	fontSourceEmbed   = "font.source_embed"
+	fontSourceDefault = "font.source_default"
+)
+
+var (
+	// 定义常见的中文字体路径（OTF/TTF 优先，TTC 需要先提取其中的简体中文字体）
+	fontPaths = []string{
+		// Noto Sans CJK SC - OTF 格式（优先级最高，Fyne 支持最好）
+		"/usr/share/fonts/opentype/noto/NotoSansCJKsc-Regular.otf",
+		"/usr/share/fonts/truetype/noto-cjk/NotoSansCJKsc-Regular.otf",
+		"/usr/share/fonts/noto-cjk/NotoSansCJKsc-Regular.otf",
+
+		// Noto Sans CJK - TTC 格式（Debian/Ubuntu fonts-noto-cjk 包）
+		"/usr/share/fonts/opentype/noto/NotoSansCJK-Regular.ttc",
+		"/usr/share/fonts/noto-cjk/NotoSansCJK-Regular.ttc",
+
+		// 麒麟/UKUI 系统字体 - TTF 格式
+		"/usr/share/fonts/truetype/ukui/ukui-default.ttf",
+		"/usr/share/fonts/ukui/ukui-default.ttf",
+		"/usr/share/fonts/truetype/kylin-font/kylin-font.ttf",
+
+		// 文泉驿字体 - TTF 格式
+		"/usr/share/fonts/truetype/wqy/wqy-microhei.ttf",
+		"/usr/share/fonts/truetype/wqy/wqy-zenhei.ttf",
+		"/usr/share/fonts/truetype/wqy/wqy-microhei.ttc",
+		"/usr/share/fonts/truetype/wqy/wqy-zenhei.ttc",
+
+		// 文鼎字体 - TTF 格式
+		"/usr/share/fonts/truetype/arphic/uming.ttf",
+		"/usr/share/fonts/truetype/arphic/ukai.ttf",
+
+		// Droid 字体
+		"/usr/share/fonts/truetype/droid/DroidSansFallbackFull.ttf",
+
+		// Windows 兼容
+		"C:\\Windows\\Fonts\\msyh.ttc",
+		"C:\\Windows\\Fonts\\simhei.ttf",
+	}
+
+	// 楷体关键字（fc-list 第一遍优先查找）
+	kaitiKeywords = []string{"KaiTi", "楷体", "Kai", "UKai", "AR PL UKai", "KAITI"}
+
+	// 粗体以外的字重（不作为常规字体候选）
+	nonRegularStyles = []string{"Bold", "Black", "Heavy", "Light", "Thin"}
+)
+
+// uiSampleText 界面文字（全部内置翻译目录和语言名称）中的非 ASCII 文字，用于检查字体是否覆盖
+var uiSampleText = catalogSampleText()
+
+// prefFontSample 上次加载的配置中出现的字符（打印机、地点名称等），启动时选择字体用
+const prefFontSample = "font_sample_text"
+
+// catalogSampleText 返回内置翻译目录中出现的非 ASCII 字符（去重并排序）
+func catalogSampleText() string {
+	var b strings.Builder
+	for _, catalog := range catalogs {
+		for _, msg := range catalog {
+			b.WriteString(msg.One + msg.Other)
+		}
+	}
+	for _, locale := range availableLocales {
+		b.WriteString(locale.label)
+	}
+	return nonASCIIRunes(b.String())
+}
+
+// configSampleText 返回配置中界面会显示的非 ASCII 字符（去重并排序，配置不变时结果不变）
+func configSampleText(config *PrinterConfig) string {
+	if config == nil {
+		return ""
+	}
+	var b strings.Builder
+	for _, location := range sortedLocations(config) {
+		b.WriteString(location + config.locationLabel(location))
+		for _, p := range config.Locations[location] {
+			b.WriteString(p.Name + p.displayName() + p.Model + p.displayDescription() + p.Floor + p.Room + p.Contact)
+			b.WriteString(strings.Join(p.Tags, ""))
+		}
+	}
+	return nonASCIIRunes(b.String())
+}
+
+// nonASCIIRunes 返回文本中需要中文字体显示的非 ASCII 字符（去重，按码位排序）
+// 只取文字、数字和标点（含中文标点）；📍 之类的符号和表情不在任何中文字体中，不参与覆盖检查
+func nonASCIIRunes(text string) string {
+	var runes []rune
+	for _, r := range uniqueRunes(text) {
+		if r > unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsNumber(r) || unicode.IsPunct(r)) {
+			runes = append(runes, r)
+		}
+	}
+	sort.Slice(runes, func(i, j int) bool { return runes[i] < runes[j] })
+	return string(runes)
+}
+
+// fontCandidate 候选字体
+type fontCandidate struct {
+	Source string
+	Path   string
+	Family string // fc-list 报告的字体名称，用于查找同名粗体
+	Reason string // 未被选中的原因
+}
+
+// fontReport 字体查找结果，供启动日志、diagnose 命令和诊断对话框使用
+type fontReport struct {
+	Source   string // 选中字体的来源，未找到时为 fontSourceDefault
+	Path     string
+	Face     string // 从 TTC 中提取的字体名称
+	Size     int
+	BoldPath string // 单独的粗体文件，为空表示粗体使用常规字体
+	Rejected []fontCandidate
+
+	Total   int    // 检查的字符数
+	Missing []rune // 选中字体缺少的字符
+}
+
+// loadedFont 读取并校验过的候选字体
+type loadedFont struct {
+	fontCandidate
+	data    []byte
+	face    string
+	missing []rune
+}
+
+// findFont 查找覆盖 sample 中字符最多的中文字体，返回查找报告、常规字体和粗体数据（未找到时为 nil）
+// 候选顺序：fc-list 楷体 → fc-list 中文字体 → 预定义路径 → 内置字体，覆盖相同时取靠前的候选；
+// 环境变量 FYNE_FONT 指定的字体只要包含所需字符就优先使用（不比较覆盖）
+func findFont(sample string) (*fontReport, []byte, []byte) {
+	sample = uniqueRunes(uiSampleText + sample)
+	r := &fontReport{Total: len([]rune(sample))}
+
+	if envFont := os.Getenv("FYNE_FONT"); envFont != "" {
+		if font := r.load(fontCandidate{Source: fontSourceEnv, Path: envFont}, sample); font != nil {
+			r.use(font)
+			return r, font.data, font.data
+		}
+	}
+
+	candidates, bolds := fcListCandidates(r)
+	for _, path := range fontPaths {
+		candidates = append(candidates, fontCandidate{Source: fontSourceStatic, Path: path})
+	}
+
+	var best *loadedFont
+	var others []*loadedFont
+	tried := make(map[string]bool)
+	for _, candidate := range candidates {
+		if tried[candidate.Path] {
+			continue
+		}
+		tried[candidate.Path] = true
+
+		font := r.load(candidate, sample)
+		if font == nil {
+			continue
+		}
+		if best == nil || len(font.missing) < len(best.missing) {
+			if best != nil {
+				others = append(others, best)
+			}
+			best = font
+		} else {
+			others = append(others, font)
+		}
+		if len(best.missing) == 0 {
+			break // 完整覆盖，不必再读取其余字体文件
+		}
+	}
+
+	// 内置字体子集只在系统字体都不完整时使用
+	if best == nil || len(best.missing) > 0 {
+		if data := embeddedFont(); data != nil {
+			font := &loadedFont{fontCandidate: fontCandidate{Source: fontSourceEmbed, Path: tr("font.embedded_path", embeddedFontPath)}, data: data}
+			font.missing, _ = missingGlyphs(data, sample)
+			if best == nil || len(font.missing) < len(best.missing) {
+				if best != nil {
+					others = append(others, best)
+				}
+				best = font
+			}
+		} else {
+			r.Rejected = append(r.Rejected, fontCandidate{Source: fontSourceEmbed, Path: embeddedFontPath, Reason: tr("font.reason_not_built")})
+		}
+	}
+
+	for _, font := range others {
+		font.Reason = tr("font.reason_missing", len(font.missing))
+		r.Rejected = append(r.Rejected, font.fontCandidate)
+	}
+	if best == nil {
+		r.Source = fontSourceDefault
+		return r, nil, nil
+	}
+
+	r.use(best)
+	return r, best.data, r.findBold(best, bolds, sample)
+}
+
+// fcListCandidates 用 fc-list 列出中文字体：楷体在前，其余在后
+// 粗体单独返回（字体名称 → 路径），用于给选中的字体配对
+func fcListCandidates(r *fontReport) ([]fontCandidate, map[string]string) {
+	bolds := make(map[string]string)
+	output, err := exec.Command("fc-list", ":lang=zh", "file", "family", "style").Output()
+	if err != nil {
+		r.Rejected = append(r.Rejected, fontCandidate{Source: fontSourceFcList, Path: "fc-list", Reason: tr("font.reason_command", err)})
+		return nil, bolds
+	}
+
+	var kaiti, others []fontCandidate
+	for _, line := range strings.Split(string(output), "\n") {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		path, family, style := parseFcListLine(line)
+		if strings.Contains(style, "Bold") {
+			if _, ok := bolds[family]; !ok {
+				bolds[family] = path
+			}
+			continue
+		}
+		if !isRegularStyle(style) {
+			continue
+		}
+		if isKaiti(line) {
+			kaiti = append(kaiti, fontCandidate{Source: fontSourceKaiti, Path: path, Family: family})
+		} else {
+			others = append(others, fontCandidate{Source: fontSourceFcList, Path: path, Family: family})
+		}
+	}
+	return append(kaiti, others...), bolds
+}
+
+// load 读取候选字体并检查字符覆盖，失败或不含任何所需字符时记录原因并返回 nil
+// 以解析校验代替按文件名屏蔽：无法解析的字体（如部分老旧 GB2312 字体）不会交给 Fyne 渲染
+func (r *fontReport) load(candidate fontCandidate, sample string) *loadedFont {
+	reject := func(reason string) *loadedFont {
+		candidate.Reason = reason
+		r.Rejected = append(r.Rejected, candidate)
+		return nil
+	}
+
+	data, face, err := readFontFile(candidate.Path)
+	if err != nil {
+		return reject(err.Error())
+	}
+	missing, err := missingGlyphs(data, sample)
+	if err != nil {
+		return reject(tr("font.reason_parse", err))
+	}
+	if len(missing) == len([]rune(sample)) {
+		return reject(tr("font.reason_no_cjk"))
+	}
+	return &loadedFont{fontCandidate: candidate, data: data, face: face, missing: missing}
+}
+
+// use 记录选中的字体
+func (r *fontReport) use(font *loadedFont) {
+	r.Source = font.Source
+	r.Path = font.Path
+	r.Face = font.face
+	r.Size = len(font.data)
+	r.Missing = font.missing
+}
+
+// findBold 查找与常规字体配套的粗体：先按 fc-list 中的同名字体，再按文件名中的 Regular → Bold
+// 找不到或覆盖不如常规字体时使用常规字体
+func (r *fontReport) findBold(regular *loadedFont, bolds map[string]string, sample string) []byte {
+	paths := make([]string, 0, 2)
+	if path, ok := bolds[regular.Family]; ok && regular.Family != "" {
+		paths = append(paths, path)
+	}
+	if strings.Contains(regular.Path, "Regular") {
+		paths = append(paths, strings.Replace(regular.Path, "Regular", "Bold", 1))
+	}
+
+	for _, path := range paths {
+		data, _, err := readFontFile(path)
+		if err != nil {
+			continue
+		}
+		if missing, err := missingGlyphs(data, sample); err != nil || len(missing) > len(regular.missing) {
+			continue
+		}
+		r.BoldPath = path
+		return data
+	}
+	return regular.data
+}
+
+// readFontFile 读取字体文件，TTC 字体集合提取其中的简体中文字体
+func readFontFile(path string) ([]byte, string, error) {
+	if _, err := os.Stat(path); err != nil {
+		return nil, "", errors.New(tr("font.reason_not_found"))
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, "", fmt.Errorf(tr("font.reason_read"), err)
+	}
+	if strings.HasSuffix(strings.ToLower(path), ".ttc") {
+		data, face, err := extractCollectionFace(data)
+		if err != nil {
+			return nil, "", fmt.Errorf(tr("font.reason_ttc"), err)
+		}
+		return data, face, nil
+	}
+	return data, "", nil
+}
+
+// parseFcListLine 解析 fc-list 输出行（"路径: 字体名,别名:style=样式,别名"），名称只取第一个
+func parseFcListLine(line string) (path, family, style string) {
+	parts := strings.SplitN(line, ":", 3)
+	path = strings.TrimSpace(parts[0])
+	if len(parts) > 1 {
+		family = strings.TrimSpace(strings.SplitN(parts[1], ",", 2)[0])
+	}
+	if len(parts) > 2 {
+		style = strings.TrimPrefix(strings.TrimSpace(parts[2]), "style=")
+	}
+	return path, family, style
+}
+
+// isRegularStyle 是否为常规字重（未报告样式时也视为常规）
+func isRegularStyle(style string) bool {
+	for _, s := range nonRegularStyles {
+		if strings.Contains(style, s) {
+			return false
+		}
+	}
+	return true
+}
+
+// isKaiti fc-list 输出行是否为楷体
+func isKaiti(line string) bool {
+	for _, keyword := range kaitiKeywords {
+		if strings.Contains(line, keyword) {
+			return true
+		}
+	}
+	return false
+}
+
+// uniqueRunes 去掉重复字符和空白
+func uniqueRunes(text string) string {
+	seen := make(map[rune]bool)
+	var b strings.Builder
+	for _, r := range text {
+		if seen[r] || unicode.IsSpace(r) {
+			continue
+		}
+		seen[r] = true
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// missingGlyphs 返回字体中缺少的字符
+func missingGlyphs(data []byte, text string) ([]rune, error) {
+	f, err := sfnt.Parse(data)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf sfnt.Buffer
+	var missing []rune
+	seen := make(map[rune]bool)
+	for _, r := range text {
+		if seen[r] {
+			continue
+		}
+		seen[r] = true
+		if index, err := f.GlyphIndex(&buf, r); err != nil || index == 0 {
+			missing = append(missing, r)
+		}
+	}
+	return missing, nil
+}
+
+// OK 是否找到了完整覆盖界面字符的中文字体
+func (r *fontReport) OK() bool {
+	return r.Path != "" && len(r.Missing) == 0
+}
+
+// Summary 一行结果，用于启动日志
+func (r *fontReport) Summary() string {
+	switch {
+	case r.Path == "":
+		return "! " + tr("font.summary_none")
+	case len(r.Missing) > 0:
+		return "! " + tr("font.summary_missing", r.Path, len(r.Missing))
+	}
+	return "✓ " + tr("font.summary_ok", r.Path, tr(r.Source))
+}
+
+// String 完整报告
+func (r *fontReport) String() string {
+	var b strings.Builder
+	b.WriteString(tr("font.report_source", tr(r.Source)) + "\n")
+	if r.Path != "" {
+		b.WriteString(tr("font.report_file", r.Path, r.Size) + "\n")
+		if r.Face != "" {
+			b.WriteString(tr("font.report_face", r.Face) + "\n")
+		}
+		if r.BoldPath != "" {
+			b.WriteString(tr("font.report_bold", r.BoldPath) + "\n")
+		} else {
+			b.WriteString(tr("font.report_no_bold") + "\n")
+		}
+		switch {
+		case len(r.Missing) > 0:
+			b.WriteString(tr("font.report_missing", len(r.Missing), r.Total, string(r.Missing)) + "\n")
+		default:
+			b.WriteString(tr("font.report_complete", r.Total) + "\n")
+		}
+	} else {
+		b.WriteString(tr("font.report_install") + "\n")
+	}
+
+	if len(r.Rejected) > 0 {
+		b.WriteString("\n" + tr("font.report_rejected", len(r.Rejected)) + "\n")
+		for _, c := range r.Rejected {
+			fmt.Fprintf(&b, "  [%s] %s: %s\n", tr(c.Source), c.Path, c.Reason)
+		}
+	}
+	return b.String()
+}
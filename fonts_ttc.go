@@ -0,0 +1,113 @@
+package main
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"strings"
+
+	"golang.org/x/image/font/sfnt"
+)
+
+// Fyne 只能加载单个 TTF/OTF 字体，TTC 字体集合（Noto CJK、文泉驿等）需要先取出其中一个字体
+
+// extractCollectionFace 从 TTC 字体集合中选出简体中文字体，返回独立的字体数据和字体名称
+func extractCollectionFace(data []byte) ([]byte, string, error) {
+	if len(data) < 12 || string(data[:4]) != "ttcf" {
+		return nil, "", errors.New(tr("font.ttc_not_collection"))
+	}
+	collection, err := sfnt.ParseCollection(data)
+	if err != nil {
+		return nil, "", err
+	}
+
+	count := collection.NumFonts()
+	if len(data) < 12+4*count {
+		return nil, "", errors.New(tr("font.ttc_header"))
+	}
+
+	// 按名称表中的字体名选择：优先简体中文（SC），其次非等宽字体
+	var buf sfnt.Buffer
+	best, bestScore, bestName := -1, -1, ""
+	for i := 0; i < count; i++ {
+		f, err := collection.Font(i)
+		if err != nil {
+			continue
+		}
+		name, err := f.Name(&buf, sfnt.NameIDFull)
+		if err != nil || name == "" {
+			name, _ = f.Name(&buf, sfnt.NameIDFamily)
+		}
+		if score := faceScore(name); score > bestScore {
+			best, bestScore, bestName = i, score, name
+		}
+	}
+	if best < 0 {
+		return nil, "", errors.New(tr("font.ttc_no_face"))
+	}
+
+	offset := binary.BigEndian.Uint32(data[12+4*best:])
+	face, err := extractFace(data, offset)
+	if err != nil {
+		return nil, "", err
+	}
+	if _, err := sfnt.Parse(face); err != nil {
+		return nil, "", fmt.Errorf(tr("font.ttc_parse"), err)
+	}
+	return face, bestName, nil
+}
+
+// faceScore 字体名称的优先级
+func faceScore(name string) int {
+	score := 0
+	for _, word := range strings.Fields(name) {
+		switch word {
+		case "SC", "CN", "GB":
+			score += 4
+		case "Mono":
+			score -= 2
+		}
+	}
+	if strings.Contains(name, "Simplified") || strings.Contains(name, "简") {
+		score += 4
+	}
+	return score + 2
+}
+
+// extractFace 把集合中指定偏移处的字体复制为独立的字体文件
+// 表数据原样复制（校验和不变），只重写表目录中的偏移
+func extractFace(data []byte, offset uint32) ([]byte, error) {
+	if uint64(offset)+12 > uint64(len(data)) {
+		return nil, errors.New(tr("font.ttc_offset"))
+	}
+	dir := data[offset:]
+	numTables := int(binary.BigEndian.Uint16(dir[4:]))
+	if numTables == 0 || 12+16*numTables > len(dir) {
+		return nil, errors.New(tr("font.ttc_directory"))
+	}
+
+	headerSize := 12 + 16*numTables
+	out := make([]byte, headerSize, headerSize+len(data)/4)
+	copy(out, dir[:12]) // sfntVersion、numTables、searchRange 等保持不变
+
+	for i := 0; i < numTables; i++ {
+		record := dir[12+16*i : 12+16*(i+1)]
+		tableOffset := binary.BigEndian.Uint32(record[8:])
+		tableLength := binary.BigEndian.Uint32(record[12:])
+		end := uint64(tableOffset) + uint64(tableLength)
+		if end > uint64(len(data)) {
+			return nil, fmt.Errorf(tr("font.ttc_table"), record[:4])
+		}
+
+		newRecord := out[12+16*i : 12+16*(i+1)]
+		copy(newRecord, record[:8]) // tag 和 checksum
+		binary.BigEndian.PutUint32(newRecord[8:], uint32(len(out)))
+		binary.BigEndian.PutUint32(newRecord[12:], tableLength)
+
+		out = append(out, data[tableOffset:end]...)
+		for len(out)%4 != 0 {
+			out = append(out, 0)
+		}
+	}
+	return out, nil
+}
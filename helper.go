@@ -0,0 +1,215 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/godbus/dbus/v5"
+)
+
+// 特权助手的 D-Bus 名称
+const (
+	helperBusName    = "com.kylin.printer.Helper"
+	helperObjectPath = dbus.ObjectPath("/com/kylin/printer/Helper")
+	helperInterface  = "com.kylin.printer.Helper"
+
+	// polkit 动作 ID，定义见 packaging/polkit/com.kylin.printer.installer.policy
+	polkitActionID = "com.kylin.printer.installer.manage-queues"
+
+	// 接收的 PPD 文件大小上限
+	maxPPDSize = 16 << 20
+
+	// 空闲超时后自动退出（由 D-Bus 按需再次启动）
+	helperIdleTimeout = 2 * time.Minute
+)
+
+// helperService 特权助手导出的 D-Bus 对象
+// 只提供白名单内的队列操作：创建队列、删除队列
+type helperService struct {
+	conn     *dbus.Conn
+	polkit   bool // 是否通过 polkit 校验调用者
+	dryRun   bool // 只打印将要执行的命令，不真正修改 CUPS
+	mutex    sync.Mutex
+	activity chan struct{}
+}
+
+// runHelper 运行特权助手（printer-installer helper [--session] [--dry-run]）
+func runHelper(args []string) int {
+	flags := flag.NewFlagSet("helper", flag.ContinueOnError)
+	session := flags.Bool("session", false, "使用会话总线并跳过 polkit 校验（仅用于本地测试）")
+	dryRun := flags.Bool("dry-run", false, "只打印 lpadmin 命令，不修改 CUPS 配置")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+
+	if !*session && os.Geteuid() != 0 {
+		fmt.Fprintln(os.Stderr, "特权助手需要以 root 身份运行（或使用 --session 进行本地测试）")
+		return 1
+	}
+
+	conn, err := connectHelperBus(*session)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "连接 D-Bus 失败: %v\n", err)
+		return 1
+	}
+	defer conn.Close()
+
+	service := &helperService{
+		conn:     conn,
+		polkit:   !*session,
+		dryRun:   *dryRun,
+		activity: make(chan struct{}, 1),
+	}
+	if err := conn.Export(service, helperObjectPath, helperInterface); err != nil {
+		fmt.Fprintf(os.Stderr, "导出 D-Bus 对象失败: %v\n", err)
+		return 1
+	}
+
+	reply, err := conn.RequestName(helperBusName, dbus.NameFlagDoNotQueue)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "申请 D-Bus 名称失败: %v\n", err)
+		return 1
+	}
+	if reply != dbus.RequestNameReplyPrimaryOwner {
+		fmt.Fprintf(os.Stderr, "D-Bus 名称 %s 已被占用\n", helperBusName)
+		return 1
+	}
+
+	fmt.Printf("特权助手已启动 (%s, polkit: %v, dry-run: %v)\n", helperBusName, service.polkit, service.dryRun)
+
+	// 空闲一段时间后退出
+	timer := time.NewTimer(helperIdleTimeout)
+	for {
+		select {
+		case <-service.activity:
+			if !timer.Stop() {
+				<-timer.C
+			}
+			timer.Reset(helperIdleTimeout)
+		case <-timer.C:
+			fmt.Println("特权助手空闲超时，退出")
+			return 0
+		}
+	}
+}
+
+// AddPrinter 创建（或替换）打印队列，ppd 为 PPD 文件内容
+func (s *helperService) AddPrinter(sender dbus.Sender, name, uri, info, location string, ppd []byte) *dbus.Error {
+	s.touch()
+	if err := s.authorize(sender); err != nil {
+		return err
+	}
+
+	q := printerQueue{Name: name, URI: uri, Info: info, Location: location}
+	if err := validateQueue(q); err != nil {
+		return helperError("InvalidArgs", err)
+	}
+	if len(ppd) == 0 || len(ppd) > maxPPDSize {
+		return helperError("InvalidArgs", fmt.Errorf("PPD 文件大小无效 (%d bytes)", len(ppd)))
+	}
+
+	tempFile, err := os.CreateTemp("", "printer-helper-*.ppd")
+	if err != nil {
+		return helperError("Failed", fmt.Errorf("创建临时文件失败: %v", err))
+	}
+	defer os.Remove(tempFile.Name())
+	_, err = tempFile.Write(ppd)
+	tempFile.Close()
+	if err != nil {
+		return helperError("Failed", fmt.Errorf("保存PPD文件失败: %v", err))
+	}
+
+	// 串行执行，避免并发修改同名队列
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.dryRun {
+		fmt.Printf("[dry-run] %s lpadmin %s\n", sender, strings.Join(q.lpadminArgs(tempFile.Name()), " "))
+		return nil
+	}
+	if err := addPrinterQueue(q, tempFile.Name()); err != nil {
+		return helperError("Failed", err)
+	}
+	fmt.Printf("%s 创建打印队列: %s\n", sender, q.Name)
+	return nil
+}
+
+// DeletePrinter 删除打印队列
+func (s *helperService) DeletePrinter(sender dbus.Sender, name string) *dbus.Error {
+	s.touch()
+	if err := s.authorize(sender); err != nil {
+		return err
+	}
+
+	if err := validateQueueName(name); err != nil {
+		return helperError("InvalidArgs", err)
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.dryRun {
+		fmt.Printf("[dry-run] %s lpadmin -x %s\n", sender, name)
+		return nil
+	}
+	if err := deletePrinterQueue(name); err != nil {
+		return helperError("Failed", err)
+	}
+	fmt.Printf("%s 删除打印队列: %s\n", sender, name)
+	return nil
+}
+
+// touch 记录一次调用，重置空闲计时
+func (s *helperService) touch() {
+	select {
+	case s.activity <- struct{}{}:
+	default:
+	}
+}
+
+// polkitSubject polkit CheckAuthorization 的 subject 参数 (sa{sv})
+type polkitSubject struct {
+	Kind    string
+	Details map[string]dbus.Variant
+}
+
+// polkitResult polkit CheckAuthorization 的返回值 (bba{ss})
+type polkitResult struct {
+	IsAuthorized bool
+	IsChallenge  bool
+	Details      map[string]string
+}
+
+// authorize 通过 polkit 校验调用者是否有权管理打印队列（必要时弹出认证对话框）
+func (s *helperService) authorize(sender dbus.Sender) *dbus.Error {
+	if !s.polkit {
+		return nil
+	}
+
+	subject := polkitSubject{
+		Kind:    "system-bus-name",
+		Details: map[string]dbus.Variant{"name": dbus.MakeVariant(string(sender))},
+	}
+	const allowUserInteraction = uint32(1)
+
+	var result polkitResult
+	authority := s.conn.Object("org.freedesktop.PolicyKit1", "/org/freedesktop/PolicyKit1/Authority")
+	err := authority.Call("org.freedesktop.PolicyKit1.Authority.CheckAuthorization", 0,
+		subject, polkitActionID, map[string]string{}, allowUserInteraction, "").Store(&result)
+	if err != nil {
+		return helperError("Failed", fmt.Errorf("polkit 授权检查失败: %v", err))
+	}
+	if !result.IsAuthorized {
+		return helperError("NotAuthorized", fmt.Errorf("未获得管理打印机的授权"))
+	}
+	return nil
+}
+
+// helperError 构造特权助手返回的 D-Bus 错误
+func helperError(name string, err error) *dbus.Error {
+	return dbus.NewError(helperInterface+".Error."+name, []interface{}{err.Error()})
+}
@@ -0,0 +1,190 @@
+package main
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"sort"
+	"strings"
+)
+
+// 界面文字的翻译目录（locales/<语言>.json），键为消息 ID
+//
+// 普通消息写作字符串；需要区分单复数的消息写作 {"one": "...", "other": "..."}，
+// 用 trn 格式化，第一个参数为数量。中文没有单复数，只需 other。
+//
+//go:embed locales/*.json
+var localeFiles embed.FS
+
+// defaultLocale 找不到匹配的翻译时使用的语言
+const defaultLocale = "zh_CN"
+
+// prefLanguage 界面语言（为空表示跟随系统 LANG/LC_MESSAGES）
+const prefLanguage = "language"
+
+// message 一条翻译，单复数不同时 One 非空
+type message struct {
+	One   string `json:"one"`
+	Other string `json:"other"`
+}
+
+// UnmarshalJSON 同时支持字符串和 {"one", "other"} 两种写法
+func (m *message) UnmarshalJSON(data []byte) error {
+	var text string
+	if err := json.Unmarshal(data, &text); err == nil {
+		m.Other = text
+		return nil
+	}
+	type plain message
+	return json.Unmarshal(data, (*plain)(m))
+}
+
+var (
+	catalogs      = loadCatalogs()
+	currentLocale = defaultLocale
+)
+
+// loadCatalogs 读取内置的全部翻译目录
+func loadCatalogs() map[string]map[string]message {
+	result := make(map[string]map[string]message)
+	entries, err := localeFiles.ReadDir("locales")
+	if err != nil {
+		return result
+	}
+	for _, entry := range entries {
+		data, err := localeFiles.ReadFile("locales/" + entry.Name())
+		if err != nil {
+			continue
+		}
+		catalog := make(map[string]message)
+		if err := json.Unmarshal(data, &catalog); err != nil {
+			fmt.Printf("⚠ 翻译文件 %s 格式错误: %v\n", entry.Name(), err)
+			continue
+		}
+		result[strings.TrimSuffix(entry.Name(), path.Ext(entry.Name()))] = catalog
+	}
+	return result
+}
+
+// availableLocales 可选的界面语言及其显示名称（显示名称使用该语言本身）
+var availableLocales = []struct {
+	locale string
+	label  string
+}{
+	{"zh_CN", "简体中文"},
+	{"en_US", "English"},
+}
+
+// detectLocale 按 LC_ALL → LC_MESSAGES → LANG 的顺序确定界面语言
+func detectLocale(getenv func(string) string) string {
+	for _, name := range []string{"LC_ALL", "LC_MESSAGES", "LANG"} {
+		if locale := matchLocale(getenv(name)); locale != "" {
+			return locale
+		}
+	}
+	return defaultLocale
+}
+
+// matchLocale 把 zh_CN.UTF-8、en-GB、zh 之类的语言标识对应到已有的翻译，没有时返回空字符串
+func matchLocale(value string) string {
+	value = strings.TrimSpace(value)
+	if i := strings.IndexAny(value, ".@"); i >= 0 {
+		value = value[:i]
+	}
+	value = strings.ReplaceAll(value, "-", "_")
+	if value == "" || value == "C" || value == "POSIX" {
+		return ""
+	}
+	if _, ok := catalogs[value]; ok {
+		return value
+	}
+
+	// 只匹配语言部分，如 en_GB → en_US
+	lang := strings.ToLower(strings.SplitN(value, "_", 2)[0])
+	for _, item := range availableLocales {
+		if strings.HasPrefix(item.locale, lang+"_") {
+			return item.locale
+		}
+	}
+	return ""
+}
+
+// initLocale 设置界面语言：Preferences 中手动选择的语言优先，否则跟随系统
+func initLocale(preferred string) {
+	if locale := matchLocale(preferred); locale != "" {
+		currentLocale = locale
+		return
+	}
+	currentLocale = detectLocale(os.Getenv)
+}
+
+// lookup 查找当前语言的翻译，缺失时依次回退到默认语言和消息 ID 本身
+func lookup(key string) message {
+	if msg, ok := catalogs[currentLocale][key]; ok {
+		return msg
+	}
+	if msg, ok := catalogs[defaultLocale][key]; ok {
+		return msg
+	}
+	return message{Other: key}
+}
+
+// tr 返回翻译后的文字，有参数时按 fmt.Sprintf 格式化
+func tr(key string, args ...interface{}) string {
+	text := lookup(key).Other
+	if len(args) == 0 {
+		return text
+	}
+	return fmt.Sprintf(text, args...)
+}
+
+// trn 按数量 n 选择单复数形式，n 作为第一个格式化参数
+func trn(key string, n int, args ...interface{}) string {
+	msg := lookup(key)
+	text := msg.Other
+	if msg.One != "" && pluralOne(currentLocale, n) {
+		text = msg.One
+	}
+	return fmt.Sprintf(text, append([]interface{}{n}, args...)...)
+}
+
+// pluralOne 当前语言下数量 n 是否使用单数形式
+func pluralOne(locale string, n int) bool {
+	if strings.HasPrefix(locale, "zh") {
+		return false
+	}
+	return n == 1
+}
+
+// localizedText 配置中按语言区分的文字，键为 zh_CN、en_US、en 等语言标识
+type localizedText map[string]string
+
+// text 返回当前界面语言的文字：先精确匹配，再按语言部分匹配（如 en → en_US），都没有时返回 fallback
+func (t localizedText) text(fallback string) string {
+	if value := strings.TrimSpace(t[currentLocale]); value != "" {
+		return value
+	}
+	keys := make([]string, 0, len(t))
+	for key := range t {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		if value := strings.TrimSpace(t[key]); value != "" && matchLocale(key) == currentLocale {
+			return value
+		}
+	}
+	return fallback
+}
+
+// values 返回全部语言的文字（用于搜索）
+func (t localizedText) values() []string {
+	values := make([]string, 0, len(t))
+	for _, value := range t {
+		values = append(values, value)
+	}
+	sort.Strings(values)
+	return values
+}
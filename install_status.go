@@ -0,0 +1,292 @@
+package main
+
+import (
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// installState 单台打印机的安装状态
+type installState int
+
+const (
+	installNone        installState = iota // 未安装（不显示）
+	installPending                         // 等待安装
+	installDownloading                     // 正在下载 PPD
+	installConfiguring                     // 正在创建打印队列
+	installDone                            // 安装成功
+	installFailed                          // 安装失败
+)
+
+// installStatus 列表中显示的安装状态，键为 printerKey
+type installStatus struct {
+	State    installState
+	Error    string // 失败原因（含 lpadmin 的完整输出）
+	Expanded bool   // 是否展开错误详情
+}
+
+// icon 状态图标
+func (s installState) icon() fyne.Resource {
+	switch s {
+	case installPending:
+		return theme.HistoryIcon()
+	case installDownloading:
+		return theme.DownloadIcon()
+	case installConfiguring:
+		return theme.SettingsIcon()
+	case installDone:
+		return theme.ConfirmIcon()
+	case installFailed:
+		return theme.ErrorIcon()
+	}
+	return nil
+}
+
+// label 状态文字（图标旁显示，也便于键盘和读屏用户确认状态）
+func (s installState) label() string {
+	switch s {
+	case installPending:
+		return tr("install.state_pending")
+	case installDownloading:
+		return tr("install.state_downloading")
+	case installConfiguring:
+		return tr("install.state_configuring")
+	case installDone:
+		return tr("install.state_done")
+	case installFailed:
+		return tr("install.state_failed")
+	}
+	return ""
+}
+
+// errorRows 错误详情框显示的行数
+const errorRows = 4
+
+// newInstallErrorBox 创建列表行中的错误详情区域：[显示详情] [重试] + 完整错误输出
+func newInstallErrorBox() *fyne.Container {
+	toggleBtn := widget.NewButtonWithIcon(tr("install.show_error"), theme.MenuDropDownIcon(), nil)
+	toggleBtn.Importance = widget.LowImportance
+	retryBtn := widget.NewButtonWithIcon(tr("install.retry"), theme.ViewRefreshIcon(), nil)
+
+	// 只读的多行输入框：可滚动、可选中复制
+	errorText := widget.NewMultiLineEntry()
+	errorText.Wrapping = fyne.TextWrapWord
+	errorText.SetMinRowsVisible(errorRows)
+	errorText.Disable()
+
+	box := container.NewVBox(container.NewHBox(toggleBtn, retryBtn), errorText)
+	box.Hide()
+	return box
+}
+
+// printerRowParts 列表行中与勾选和安装状态有关的控件
+type printerRowParts struct {
+	check      *widget.Check
+	stateIcon  *widget.Icon
+	stateLabel *widget.Label
+	errorBox   *fyne.Container
+	toggleBtn  *widget.Button
+	retryBtn   *widget.Button
+	errorText  *widget.Entry
+}
+
+// rowParts 从列表行（见 initUI 中的 CreateItem）中取出控件
+func rowParts(item fyne.CanvasObject) printerRowParts {
+	var parts printerRowParts
+	box, ok := item.(*fyne.Container)
+	if !ok || len(box.Objects) == 0 {
+		return parts
+	}
+	infoBox, ok := box.Objects[0].(*fyne.Container)
+	if !ok || len(infoBox.Objects) < 4 {
+		return parts
+	}
+
+	header := infoBox.Objects[0].(*fyne.Container)
+	parts.check = header.Objects[0].(*widget.Check)
+	parts.stateIcon = header.Objects[1].(*widget.Icon)
+	parts.stateLabel = header.Objects[2].(*widget.Label)
+
+	parts.errorBox = infoBox.Objects[3].(*fyne.Container)
+	buttons := parts.errorBox.Objects[0].(*fyne.Container)
+	parts.toggleBtn = buttons.Objects[0].(*widget.Button)
+	parts.retryBtn = buttons.Objects[1].(*widget.Button)
+	parts.errorText = parts.errorBox.Objects[1].(*widget.Entry)
+	return parts
+}
+
+// applyStatus 按安装状态显示图标、状态文字和错误详情（不绑定按钮事件）
+func (p printerRowParts) applyStatus(status installStatus) {
+	if status.State == installNone {
+		p.stateIcon.Hide()
+		p.stateLabel.Hide()
+	} else {
+		p.stateIcon.SetResource(status.State.icon())
+		p.stateIcon.Show()
+		p.stateLabel.SetText(status.State.label())
+		p.stateLabel.Show()
+	}
+
+	if status.State != installFailed {
+		p.errorBox.Hide()
+		return
+	}
+	p.errorBox.Show()
+	if status.Expanded {
+		p.toggleBtn.SetText(tr("install.hide_error"))
+		p.toggleBtn.SetIcon(theme.MenuDropUpIcon())
+		p.errorText.SetText(status.Error)
+		p.errorText.Show()
+	} else {
+		p.toggleBtn.SetText(tr("install.show_error"))
+		p.toggleBtn.SetIcon(theme.MenuDropDownIcon())
+		p.errorText.Hide()
+	}
+}
+
+// showInstallStatus 在列表行中显示打印机的安装状态
+func (gui *PrinterInstallerGUI) showInstallStatus(parts printerRowParts, row PrinterRow) {
+	if parts.check == nil {
+		return
+	}
+	key := printerKey(row.Location, row.Printer)
+
+	gui.mutex.Lock()
+	status := installStatus{}
+	if s, ok := gui.installStates[key]; ok {
+		status = *s
+	}
+	installing := gui.installing
+	gui.mutex.Unlock()
+
+	parts.applyStatus(status)
+	parts.toggleBtn.OnTapped = func() {
+		gui.toggleInstallError(key)
+	}
+	parts.retryBtn.OnTapped = func() {
+		go gui.retryPrinter(row)
+	}
+	if installing {
+		parts.retryBtn.Disable()
+	} else {
+		parts.retryBtn.Enable()
+	}
+}
+
+// rowHeight 指定安装状态下列表行的高度（用模板行计算，与 CreateItem 一致）
+func (gui *PrinterInstallerGUI) rowHeight(status installStatus) float32 {
+	item := gui.printerTable.CreateItem()
+	rowParts(item).applyStatus(status)
+	return item.MinSize().Height
+}
+
+// setInstallState 更新一台打印机的安装状态并刷新对应的列表行
+func (gui *PrinterInstallerGUI) setInstallState(key string, state installState, errMsg string) {
+	gui.mutex.Lock()
+	status, ok := gui.installStates[key]
+	if !ok {
+		status = &installStatus{}
+		gui.installStates[key] = status
+	}
+	status.State = state
+	status.Error = errMsg
+	if state != installFailed {
+		status.Expanded = false
+	}
+	gui.mutex.Unlock()
+
+	gui.refreshRow(key)
+}
+
+// toggleInstallError 展开或收起一台打印机的错误详情
+func (gui *PrinterInstallerGUI) toggleInstallError(key string) {
+	gui.mutex.Lock()
+	if status, ok := gui.installStates[key]; ok {
+		status.Expanded = !status.Expanded
+	}
+	gui.mutex.Unlock()
+
+	gui.refreshRow(key)
+}
+
+// refreshRow 按安装状态调整行高并刷新列表中的一行（不在当前列表中时忽略）
+func (gui *PrinterInstallerGUI) refreshRow(key string) {
+	gui.mutex.Lock()
+	id := -1
+	for i, row := range gui.printerData {
+		if printerKey(row.Location, row.Printer) == key {
+			id = i
+			break
+		}
+	}
+	status := installStatus{}
+	if s, ok := gui.installStates[key]; ok {
+		status = *s
+	}
+	gui.mutex.Unlock()
+
+	if id < 0 {
+		return
+	}
+	gui.printerTable.SetItemHeight(id, gui.rowHeight(status))
+	gui.printerTable.RefreshItem(id)
+}
+
+// updateRowHeights 列表内容或文字大小变化后，按各行的安装状态重新设置行高
+func (gui *PrinterInstallerGUI) updateRowHeights() {
+	gui.mutex.Lock()
+	statuses := make([]installStatus, len(gui.printerData))
+	for i, row := range gui.printerData {
+		if s, ok := gui.installStates[printerKey(row.Location, row.Printer)]; ok {
+			statuses[i] = *s
+		}
+	}
+	gui.mutex.Unlock()
+
+	heights := make(map[installState]map[bool]float32)
+	for id, status := range statuses {
+		if heights[status.State] == nil {
+			heights[status.State] = make(map[bool]float32)
+		}
+		height, ok := heights[status.State][status.Expanded]
+		if !ok {
+			height = gui.rowHeight(status)
+			heights[status.State][status.Expanded] = height
+		}
+		gui.printerTable.SetItemHeight(id, height)
+	}
+}
+
+// retryPrinter 重新安装一台失败的打印机（正在安装或加载配置时不执行）
+func (gui *PrinterInstallerGUI) retryPrinter(row PrinterRow) {
+	config, defaultKey, ok := gui.beginInstall()
+	if !ok {
+		gui.statusText.Set(tr("status.busy"))
+		return
+	}
+	gui.installBtn.Disable()
+	gui.printerTable.Refresh()
+
+	key := printerKey(row.Location, row.Printer)
+	gui.statusText.Set(tr("install.status_installing", row.Printer.displayName()))
+	success, errMsg := gui.installSinglePrinter(config, row)
+	if success {
+		gui.setInstallState(key, installDone, "")
+		// 与 installProcess 一样，设置默认打印机的结果附在安装结果后面，不覆盖它
+		msg := tr("install.retry_done", row.Printer.displayName())
+		if key == defaultKey {
+			msg = tr("install.retry_done_default", msg, gui.setDefaultPrinter(row.Printer.queueName()))
+		}
+		gui.statusText.Set(msg)
+	} else {
+		gui.setInstallState(key, installFailed, errMsg)
+		gui.statusText.Set(tr("install.retry_failed", row.Printer.displayName()))
+	}
+
+	gui.mutex.Lock()
+	gui.installing = false
+	gui.mutex.Unlock()
+	gui.updateInstallBtnState()
+	gui.printerTable.Refresh()
+}
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"path"
+	"sort"
+	"strings"
+)
+
+// prefLastLocation 记录用户上次选择的地点（Fyne Preferences 键名）
+const prefLastLocation = "last_location"
+
+// LocationRule 地点自动识别规则
+type LocationRule struct {
+	Subnets   []string `json:"subnets"`   // CIDR 网段，如 "10.245.93.0/24"
+	Hostnames []string `json:"hostnames"` // 主机名通配符，如 "gz-3f-*"
+}
+
+// localAddresses 获取本机所有网卡上的 IP 地址（忽略回环地址）
+func localAddresses() []net.IP {
+	addrs, err := net.InterfaceAddrs()
+	if err != nil {
+		fmt.Printf("获取网卡地址失败: %v\n", err)
+		return nil
+	}
+
+	ips := make([]net.IP, 0, len(addrs))
+	for _, addr := range addrs {
+		ipNet, ok := addr.(*net.IPNet)
+		if !ok || ipNet.IP.IsLoopback() {
+			continue
+		}
+		ips = append(ips, ipNet.IP)
+	}
+	return ips
+}
+
+// detectLocation 根据本机 IP 和主机名匹配配置中的地点，未匹配时返回空字符串
+func detectLocation(config *PrinterConfig) string {
+	hostname, _ := os.Hostname()
+	return matchLocation(config, localAddresses(), hostname)
+}
+
+// matchLocation 按规则匹配地点
+// 网段匹配优先，多个网段命中时取掩码最长（最精确）的一个；
+// 网段都未命中时再按主机名通配符匹配
+func matchLocation(config *PrinterConfig, ips []net.IP, hostname string) string {
+	if config == nil || len(config.LocationRules) == 0 {
+		return ""
+	}
+
+	// 按名称排序，保证结果稳定
+	names := make([]string, 0, len(config.LocationRules))
+	for name := range config.LocationRules {
+		if _, ok := config.Locations[name]; ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	bestLocation := ""
+	bestPrefix := -1
+	for _, name := range names {
+		for _, cidr := range config.LocationRules[name].Subnets {
+			_, subnet, err := net.ParseCIDR(strings.TrimSpace(cidr))
+			if err != nil {
+				fmt.Printf("  忽略无效网段 %q (%s): %v\n", cidr, name, err)
+				continue
+			}
+			ones, _ := subnet.Mask.Size()
+			for _, ip := range ips {
+				if subnet.Contains(ip) && ones > bestPrefix {
+					bestLocation = name
+					bestPrefix = ones
+				}
+			}
+		}
+	}
+	if bestLocation != "" {
+		return bestLocation
+	}
+
+	hostname = strings.ToLower(strings.TrimSpace(hostname))
+	if hostname == "" {
+		return ""
+	}
+	// 同时尝试完整主机名和短主机名（去掉域名部分）
+	candidates := []string{hostname}
+	if idx := strings.Index(hostname, "."); idx > 0 {
+		candidates = append(candidates, hostname[:idx])
+	}
+	for _, name := range names {
+		for _, pattern := range config.LocationRules[name].Hostnames {
+			for _, candidate := range candidates {
+				if ok, err := path.Match(strings.ToLower(pattern), candidate); err == nil && ok {
+					return name
+				}
+			}
+		}
+	}
+	return ""
+}
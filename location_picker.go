@@ -0,0 +1,166 @@
+package main
+
+import (
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
+)
+
+// locationPicker 级联地点选择器：每一层级一个下拉框，选中上级后出现下级
+// 下拉框显示当前界面语言的名称，选中地点有描述时显示在下拉框下方
+type locationPicker struct {
+	object    fyne.CanvasObject
+	box       *fyne.Container
+	desc      *widget.Label
+	roots     []*locationNode
+	selects   []*widget.Select
+	levels    [][]*locationNode // 每个下拉框对应的候选节点
+	labels    [][]string        // 每个下拉框的选项，与 levels 一一对应
+	selected  string
+	updating  bool // 程序设置选中项时，屏蔽中间层级的回调
+	onChanged func(key string, byUser bool)
+}
+
+// newLocationPicker 创建级联地点选择器；onChanged 的 byUser 表示是否由用户在下拉框中选择
+func newLocationPicker(onChanged func(key string, byUser bool)) *locationPicker {
+	p := &locationPicker{
+		box:       container.NewGridWithRows(1),
+		desc:      widget.NewLabel(""),
+		onChanged: onChanged,
+	}
+	p.desc.Wrapping = fyne.TextWrapWord
+	p.desc.Hide()
+	p.object = container.NewVBox(p.box, p.desc)
+	p.SetTree(nil)
+	return p
+}
+
+// Object 返回用于放入布局的界面对象
+func (p *locationPicker) Object() fyne.CanvasObject {
+	return p.object
+}
+
+// Selected 返回当前选中的完整地点名称
+func (p *locationPicker) Selected() string {
+	return p.selected
+}
+
+// SetTree 设置地点树并清空当前选择
+func (p *locationPicker) SetTree(roots []*locationNode) {
+	p.roots = roots
+	p.selected = ""
+	p.setDescription("")
+	p.truncate(0)
+	p.addLevel(roots, tr("location.placeholder"))
+}
+
+// SetSelected 按完整地点名称选中对应的各级下拉框
+func (p *locationPicker) SetSelected(key string) {
+	path := findLocationPath(p.roots, key)
+	if path == nil {
+		return
+	}
+
+	p.updating = true
+	for i, node := range path {
+		if i >= len(p.selects) {
+			break
+		}
+		for j, candidate := range p.levels[i] {
+			if candidate == node {
+				p.selects[i].SetSelected(p.labels[i][j])
+				break
+			}
+		}
+	}
+	p.updating = false
+
+	p.notify(false)
+}
+
+// addLevel 追加一个层级的下拉框
+func (p *locationPicker) addLevel(nodes []*locationNode, placeholder string) {
+	level := len(p.selects)
+	labels := optionLabels(nodes)
+
+	sel := widget.NewSelect(labels, func(label string) {
+		p.onSelect(level, label)
+	})
+	sel.PlaceHolder = placeholder
+
+	p.selects = append(p.selects, sel)
+	p.levels = append(p.levels, nodes)
+	p.labels = append(p.labels, labels)
+	p.box.Add(sel)
+}
+
+// optionLabels 下拉框选项：同级地点翻译后的名称相同时（如都译为 "Office"），
+// 在后面加上配置中的名称加以区分，保证每个选项对应唯一的地点
+func optionLabels(nodes []*locationNode) []string {
+	count := make(map[string]int)
+	for _, node := range nodes {
+		count[node.Label]++
+	}
+	labels := make([]string, 0, len(nodes))
+	for _, node := range nodes {
+		label := node.Label
+		if count[label] > 1 && node.Name != label {
+			label += " (" + node.Name + ")"
+		}
+		labels = append(labels, label)
+	}
+	return labels
+}
+
+// truncate 移除指定层级及之后的下拉框
+func (p *locationPicker) truncate(level int) {
+	if level < len(p.selects) {
+		p.selects = p.selects[:level]
+		p.levels = p.levels[:level]
+		p.labels = p.labels[:level]
+		p.box.Objects = p.box.Objects[:level]
+		p.box.Refresh()
+	}
+}
+
+// onSelect 某一层级选中后，重建下级下拉框
+func (p *locationPicker) onSelect(level int, label string) {
+	var node *locationNode
+	for i, option := range p.labels[level] {
+		if option == label {
+			node = p.levels[level][i]
+			break
+		}
+	}
+	if node == nil {
+		return
+	}
+
+	p.truncate(level + 1)
+	if len(node.Children) > 0 {
+		p.addLevel(node.Children, tr("location.placeholder_child"))
+	}
+
+	p.selected = node.Key
+	p.setDescription(node.Description)
+	if !p.updating {
+		p.notify(true)
+	}
+}
+
+// setDescription 显示选中地点的描述，为空时隐藏
+func (p *locationPicker) setDescription(text string) {
+	p.desc.SetText(text)
+	if text == "" {
+		p.desc.Hide()
+	} else {
+		p.desc.Show()
+	}
+}
+
+// notify 通知选中地点变化
+func (p *locationPicker) notify(byUser bool) {
+	if p.onChanged != nil {
+		p.onChanged(p.selected, byUser)
+	}
+}
@@ -0,0 +1,149 @@
+package main
+
+import (
+	"sort"
+	"strings"
+)
+
+// locationPathSep 层级地点拼接成完整名称时使用的分隔符
+const locationPathSep = " / "
+
+// LocationGroup 层级地点（如 园区 → 楼栋 → 楼层）
+type LocationGroup struct {
+	Name      string          `json:"name"` // 名称，拼接后作为地点的完整名称（Locations 的键）
+	Printers  []Printer       `json:"printers"`
+	Children  []LocationGroup `json:"children"`
+	Subnets   []string        `json:"subnets"`   // 同 LocationRule.Subnets
+	Hostnames []string        `json:"hostnames"` // 同 LocationRule.Hostnames
+
+	Names        localizedText `json:"names"`        // 按界面语言显示的名称（可选），不影响地点的完整名称
+	Description  string        `json:"description"`  // 描述（可选，显示在地点选择框下方）
+	Descriptions localizedText `json:"descriptions"` // 按界面语言显示的描述（可选）
+}
+
+// locationNode 地点树节点（供界面逐级选择使用）
+type locationNode struct {
+	Name        string          // 当前层级的名称（配置中的 name）
+	Label       string          // 当前界面语言下显示的名称
+	Description string          // 当前界面语言下的描述
+	Key         string          // 完整地点名称，即 Locations 的键
+	Children    []*locationNode // 下级地点
+	HasPrinters bool            // 该节点本身是否挂有打印机
+}
+
+// normalize 将层级地点展开到 Locations/LocationRules 中，并生成地点树
+// 旧版平铺的 locations 仍然有效，作为顶层地点处理
+func (c *PrinterConfig) normalize() {
+	if c.Locations == nil {
+		c.Locations = make(map[string][]Printer)
+	}
+	if c.LocationRules == nil {
+		c.LocationRules = make(map[string]LocationRule)
+	}
+	if c.PrinterModels == nil {
+		c.PrinterModels = make(map[string]PrinterModelInfo)
+	}
+
+	// 平铺地点需在展开层级地点之前记录，展开后 Locations 中还会包含层级地点的完整名称
+	flat := make([]string, 0, len(c.Locations))
+	for name := range c.Locations {
+		flat = append(flat, name)
+	}
+	sort.Strings(flat)
+
+	c.locationTree = nil
+	for _, group := range c.LocationGroups {
+		c.locationTree = c.addGroup(c.locationTree, group, nil)
+	}
+
+	// 平铺地点：排序后追加到顶层（与层级地点同名时合并）
+	for _, name := range flat {
+		node := findChild(c.locationTree, name)
+		if node == nil {
+			node = &locationNode{Name: name, Label: name, Key: name}
+			c.locationTree = append(c.locationTree, node)
+		}
+		if len(c.Locations[name]) > 0 {
+			node.HasPrinters = true
+		}
+	}
+}
+
+// addGroup 递归展开一个层级地点，返回更新后的同级节点列表
+func (c *PrinterConfig) addGroup(siblings []*locationNode, group LocationGroup, parent []string) []*locationNode {
+	name := strings.TrimSpace(group.Name)
+	if name == "" {
+		return siblings
+	}
+	path := append(append([]string{}, parent...), name)
+	key := strings.Join(path, locationPathSep)
+
+	node := findChild(siblings, name)
+	if node == nil {
+		node = &locationNode{Name: name, Label: name, Key: key}
+		siblings = append(siblings, node)
+	}
+	// 同名地点出现多次时，使用第一个提供翻译或描述的
+	if label := group.Names.text(""); label != "" && node.Label == name {
+		node.Label = label
+	}
+	if desc := group.Descriptions.text(strings.TrimSpace(group.Description)); desc != "" && node.Description == "" {
+		node.Description = desc
+	}
+
+	if len(group.Printers) > 0 {
+		c.Locations[key] = append(c.Locations[key], group.Printers...)
+		node.HasPrinters = true
+	}
+	if len(group.Subnets) > 0 || len(group.Hostnames) > 0 {
+		rule := c.LocationRules[key]
+		rule.Subnets = append(rule.Subnets, group.Subnets...)
+		rule.Hostnames = append(rule.Hostnames, group.Hostnames...)
+		c.LocationRules[key] = rule
+	}
+
+	for _, child := range group.Children {
+		node.Children = c.addGroup(node.Children, child, path)
+	}
+	return siblings
+}
+
+// findChild 按名称查找同级节点
+func findChild(nodes []*locationNode, name string) *locationNode {
+	for _, node := range nodes {
+		if node.Name == name {
+			return node
+		}
+	}
+	return nil
+}
+
+// findLocationPath 查找完整地点名称对应的节点路径（从顶层到目标节点）
+func findLocationPath(nodes []*locationNode, key string) []*locationNode {
+	for _, node := range nodes {
+		if node.Key == key {
+			return []*locationNode{node}
+		}
+		if sub := findLocationPath(node.Children, key); sub != nil {
+			return append([]*locationNode{node}, sub...)
+		}
+	}
+	return nil
+}
+
+// locationLabel 返回完整地点名称在当前界面语言下的显示名称，如 "HQ / Building A"
+// 不在地点树中时原样返回
+func (c *PrinterConfig) locationLabel(key string) string {
+	if c == nil {
+		return key
+	}
+	path := findLocationPath(c.locationTree, key)
+	if path == nil {
+		return key
+	}
+	labels := make([]string, 0, len(path))
+	for _, node := range path {
+		labels = append(labels, node.Label)
+	}
+	return strings.Join(labels, locationPathSep)
+}
@@ -1,14 +1,13 @@
 package main
 
 import (
-	"encoding/json"
+	"errors"
 	"fmt"
 	"image/color"
 	"io"
 	"net/http"
 	"net/url"
 	"os"
-	"os/exec"
 	"path/filepath"
 	"runtime/debug"
 	"sort"
@@ -26,283 +25,59 @@ import (
 	"fyne.io/fyne/v2/widget"
 )
 
-// myLightTheme 自定义亮色主题
-type myLightTheme struct {
-	regular    fyne.Resource
-	bold       fyne.Resource
-	fontLogged bool // 用于避免重复打印调试信息
-}
-
-var (
-	// 定义常见的中文字体路径（优先使用 OTF/TTF 格式，避免 TTC 兼容性问题）
-	fontPaths = []string{
-		// Noto Sans CJK SC - OTF 格式（优先级最高，Fyne 支持最好）
-		"/usr/share/fonts/opentype/noto/NotoSansCJKsc-Regular.otf",
-		"/usr/share/fonts/truetype/noto-cjk/NotoSansCJKsc-Regular.otf",
-		"/usr/share/fonts/noto-cjk/NotoSansCJKsc-Regular.otf",
-		
-		// 麒麟/UKUI 系统字体 - TTF 格式
-		"/usr/share/fonts/truetype/ukui/ukui-default.ttf",
-		"/usr/share/fonts/ukui/ukui-default.ttf",
-		"/usr/share/fonts/truetype/kylin-font/kylin-font.ttf",
-		
-		// 文泉驿字体 - TTF 格式
-		"/usr/share/fonts/truetype/wqy/wqy-microhei.ttf",
-		"/usr/share/fonts/truetype/wqy/wqy-zenhei.ttf",
-		
-		// 文鼎字体 - TTF 格式
-		"/usr/share/fonts/truetype/arphic/uming.ttf",
-		"/usr/share/fonts/truetype/arphic/ukai.ttf",
-		
-		// Droid 字体
-		"/usr/share/fonts/truetype/droid/DroidSansFallbackFull.ttf",
-		
-		// Windows 兼容
-		"C:\\Windows\\Fonts\\msyh.ttc",
-		"C:\\Windows\\Fonts\\simhei.ttf",
-	}
-)
-
-func newLightTheme() *myLightTheme {
-	theme := &myLightTheme{}
-	theme.loadFonts()
-	return theme
-}
-
-func (m *myLightTheme) loadFonts() {
-	fmt.Println("========== 开始加载字体 ==========")
-	
-	// 1. 优先检查环境变量 FYNE_FONT
-	if envFont := os.Getenv("FYNE_FONT"); envFont != "" {
-		fmt.Printf("检查环境变量 FYNE_FONT: %s\n", envFont)
-		if _, err := os.Stat(envFont); err == nil {
-			if fontData, err := os.ReadFile(envFont); err == nil {
-				m.regular = fyne.NewStaticResource("regular", fontData)
-				m.bold = fyne.NewStaticResource("bold", fontData)
-				fmt.Printf("✓ 成功：使用环境变量指定的字体 (%d bytes)\n", len(fontData))
-				fmt.Println("==================================")
-				return
-			}
-		}
-	}
-
-	// 2. 使用 fc-list 动态查找楷体字体（优先）
-	fmt.Println("\n使用 fc-list 查找楷体字体...")
-	cmd := exec.Command("fc-list", ":lang=zh", "file", "family")
-	output, err := cmd.Output()
-	
-	if err == nil {
-		lines := strings.Split(string(output), "\n")
-		
-		// 第一遍：优先查找楷体
-		kaitiKeywords := []string{"KaiTi", "楷体", "Kai", "UKai", "AR PL UKai" , "KAITI"}
-		for _, line := range lines {
-			if strings.TrimSpace(line) == "" {
-				continue
-			}
-			
-			// 检查是否包含楷体关键字
-			isKaiTi := false
-			for _, keyword := range kaitiKeywords {
-				if strings.Contains(line, keyword) {
-					isKaiTi = true
-					break
-				}
-			}
-			
-			if !isKaiTi {
-				continue
-			}
-			
-			// 提取文件路径
-			parts := strings.Split(line, ":")
-			if len(parts) > 0 {
-				fontPath := strings.TrimSpace(parts[0])
-				
-				// 跳过黑名单字体（GB2312 等老旧字体可能导致渲染崩溃）
-				fontBlacklist := []string{"_GB2312", "GB2312", "simsun.ttc"}
-				isBlackListed := false
-				for _, blocked := range fontBlacklist {
-					if strings.Contains(strings.ToLower(fontPath), strings.ToLower(blocked)) {
-						isBlackListed = true
-						break
-					}
-				}
-				if isBlackListed {
-					fmt.Printf("  跳过黑名单字体: %s\n", fontPath)
-					continue
-				}
-				
-				// 跳过 TTC 文件
-				if strings.HasSuffix(fontPath, ".ttc") {
-					fmt.Printf("  跳过 TTC: %s\n", fontPath)
-					continue
-				}
-				
-				// 尝试加载
-				if stat, err := os.Stat(fontPath); err == nil {
-					fmt.Printf("  找到楷体: %s (%d bytes)\n", fontPath, stat.Size())
-					
-					if fontData, err := os.ReadFile(fontPath); err == nil {
-						m.regular = fyne.NewStaticResource("regular", fontData)
-						m.bold = fyne.NewStaticResource("bold", fontData)
-						fmt.Printf("  ✓ 成功加载楷体！\n")
-						fmt.Println("==================================")
-						return
-					}
-				}
-			}
-		}
-		
-		fmt.Println("  未找到楷体，尝试其他中文字体...")
-		
-		// 第二遍：查找任意非 TTC 中文字体
-		for _, line := range lines {
-			if strings.TrimSpace(line) == "" {
-				continue
-			}
-			
-			parts := strings.Split(line, ":")
-			if len(parts) > 0 {
-				fontPath := strings.TrimSpace(parts[0])
-				
-				// 跳过黑名单字体
-				fontBlacklist := []string{"_GB2312", "GB2312", "simsun.ttc"}
-				isBlackListed := false
-				for _, blocked := range fontBlacklist {
-					if strings.Contains(strings.ToLower(fontPath), strings.ToLower(blocked)) {
-						isBlackListed = true
-						break
-					}
-				}
-				if isBlackListed {
-					continue
-				}
-				
-				// 跳过 TTC 文件
-				if strings.HasSuffix(fontPath, ".ttc") {
-					continue
-				}
-				
-				// 尝试加载
-				if stat, err := os.Stat(fontPath); err == nil {
-					fmt.Printf("  找到: %s (%d bytes)\n", fontPath, stat.Size())
-					
-					if fontData, err := os.ReadFile(fontPath); err == nil {
-						m.regular = fyne.NewStaticResource("regular", fontData)
-						m.bold = fyne.NewStaticResource("bold", fontData)
-						fmt.Printf("  ✓ 成功加载！\n")
-						fmt.Println("==================================")
-						return
-					}
-				}
-			}
-		}
-	} else {
-		fmt.Printf("  fc-list 命令失败: %v\n", err)
-	}
-
-	// 3. 备用方案：使用预定义的字体路径列表
-	fmt.Println("\n回退到预定义字体路径...")
-	for i, path := range fontPaths {
-		fmt.Printf("[%d/%d] 检查: %s\n", i+1, len(fontPaths), path)
-		
-		// 跳过 TTC 文件
-		if strings.HasSuffix(path, ".ttc") {
-			fmt.Printf("  ⊘ 跳过 TTC 格式\n")
-			continue
-		}
-		
-		if stat, err := os.Stat(path); err == nil {
-			fmt.Printf("  → 文件存在 (大小: %d bytes)\n", stat.Size())
-			
-			if fontData, err := os.ReadFile(path); err == nil {
-				m.regular = fyne.NewStaticResource("regular", fontData)
-				m.bold = fyne.NewStaticResource("bold", fontData)
-				fmt.Printf("  ✓ 成功加载！\n")
-				fmt.Println("==================================")
-				return
-			}
-		}
-	}
-	
-	// 4. 如果都没找到，使用默认字体
-	fmt.Println("\n! 警告: 未找到任何可用的中文字体")
-	fmt.Println("! 建议安装: sudo apt-get install fonts-noto-cjk")
-	fmt.Println("==================================")
-}
-
-// 自定义颜色
-var (
-	kylinBlue   = color.RGBA{R: 40, G: 102, B: 255, A: 255} // 麒麟蓝
-	lightBg     = color.RGBA{R: 248, G: 250, B: 252, A: 255} // 浅灰背景
-	headerColor = color.RGBA{R: 30, G: 41, B: 59, A: 255}    // 深色标题
-)
-
-func (m *myLightTheme) Color(name fyne.ThemeColorName, variant fyne.ThemeVariant) color.Color {
-	switch name {
-	case theme.ColorNamePrimary:
-		return kylinBlue
-	case theme.ColorNameBackground:
-		return lightBg
-	case theme.ColorNameInputBackground:
-		return color.White
-	}
-	return theme.DefaultTheme().Color(name, theme.VariantLight)
-}
-
-func (m *myLightTheme) Icon(name fyne.ThemeIconName) fyne.Resource {
-	return theme.DefaultTheme().Icon(name)
-}
-
-func (m *myLightTheme) Font(style fyne.TextStyle) fyne.Resource {
-	if m.regular != nil {
-		// 只在第一次调用时打印（避免刷屏）
-		if !m.fontLogged {
-			fmt.Printf("✓ 主题字体已应用 (regular: %d bytes, bold: %d bytes)\n", 
-				len(m.regular.Content()), len(m.bold.Content()))
-			m.fontLogged = true
-		}
-		
-		if style.Bold {
-			return m.bold
-		}
-		return m.regular
-	}
-	return theme.DefaultTheme().Font(style)
-}
-
-func (m *myLightTheme) Size(name fyne.ThemeSizeName) float32 {
-	if name == theme.SizeNameText {
-		return 14 // 稍微增大默认字体
-	}
-	return theme.DefaultTheme().Size(name)
-}
-
 // PrinterConfig 打印机配置结构
 type PrinterConfig struct {
-	Locations     map[string][]Printer        `json:"locations"`
-	PrinterModels map[string]PrinterModelInfo `json:"printer_models"`
+	Version          int    `json:"version"`            // 配置格式版本，见 configSchemaVersion
+	MinClientVersion string `json:"min_client_version"` // 要求的最低程序版本（可选）
+
+	Locations      map[string][]Printer        `json:"locations"`       // 平铺地点（版本 1 格式，加载时迁移到 location_groups）
+	LocationGroups []LocationGroup             `json:"location_groups"` // 层级地点（可选，与 locations 可同时使用）
+	PrinterModels  map[string]PrinterModelInfo `json:"printer_models"`
+	LocationRules  map[string]LocationRule     `json:"location_rules"` // 地点自动识别规则（可选）
+	Include        []string                    `json:"include"`        // 引用的其他配置文档（可用相对地址，如公共型号库）
+
+	locationTree []*locationNode // 由 normalize 生成的地点树
+	conflicts    []string        // 合并多个来源时发现的冲突
 }
 
 // Printer 打印机信息
 type Printer struct {
-	Name  string `json:"name"`
-	Model string `json:"model"`
-	IP    string `json:"ip"`
-	PPD   string `json:"ppd"`
-	URI   string `json:"uri"`
+	Name        string   `json:"name"`       // 显示名称，写入 CUPS printer-info
+	QueueName   string   `json:"queue_name"` // CUPS 队列名称（可选，未填写时由名称自动生成，见 queueName）
+	Model       string   `json:"model"`
+	IP          string   `json:"ip"`
+	PPD         string   `json:"ppd"`
+	URI         string   `json:"uri"`
+	Description string   `json:"description"` // 描述（可选，参与搜索）
+	Tags        []string `json:"tags"`        // 标签（可选，参与搜索）
+	Default     bool     `json:"default"`     // 是否为该地点的默认打印机
+
+	Names        localizedText `json:"names"`        // 按界面语言显示的名称（可选），不影响队列名称
+	Descriptions localizedText `json:"descriptions"` // 按界面语言显示的描述（可选）
+
+	// 以下为可选的扩展信息
+	Floor        string              `json:"floor"`        // 楼层
+	Room         string              `json:"room"`         // 房间
+	Capabilities PrinterCapabilities `json:"capabilities"` // 功能（彩色、双面、A3、装订）
+	Contact      string              `json:"contact"`      // 联系人/负责人
+	ImageURL     string              `json:"image_url"`    // 照片地址
+
+	Source        string `json:"-"` // 所属配置来源（加载时填写）
+	queue         string // 自动生成的队列名称（加载时由 assignQueueNames 填写）
+	queueConflict string // queue_name 与其他打印机重复时的说明，有值时拒绝安装
 }
 
 // PrinterModelInfo 打印机型号信息
 type PrinterModelInfo struct {
 	PPDURL string `json:"ppd_url"`
+
+	source string // 所属配置来源（用于冲突提示）
 }
 
 // PrinterRow 打印机表格行
 type PrinterRow struct {
-	Checked bool
-	Printer Printer
+	Location string
+	Printer  Printer
 }
 
 // PrinterInstallerGUI 主界面
@@ -310,20 +85,40 @@ type PrinterInstallerGUI struct {
 	app            fyne.App
 	window         fyne.Window
 	config         *PrinterConfig
-	configURL      string
-	printerData    []Printer
-	checkedItems   map[int]bool
+	configSources  []string       // 配置来源（URL 或本地文件），多个来源合并显示
+	backend        printerBackend // 打印队列操作后端（lpadmin 或特权助手）
+	httpClient     *http.Client   // 配置和 PPD 下载共用的 HTTP 客户端（由 mutex 保护）
+	httpClientErr  error          // 网络设置有误时的错误（由 mutex 保护）
+	theme          *appTheme  // 当前主题（含字体查找结果）
+	printerData    []PrinterRow
+	checkedItems   map[string]bool // 键为 printerKey(地点, 打印机)
+	defaultKey     string          // 安装完成后设为默认的打印机（printerKey），为空表示不设置
+	location       string          // 当前选中的地点
+	searching      bool            // 是否处于跨地点搜索模式
+	loading        bool            // 正在加载配置
+	installing     bool            // 正在安装打印机（期间不自动刷新配置）
 	mutex          sync.Mutex
 
+	installStates map[string]*installStatus // 列表中显示的安装状态，键为 printerKey
+
+	restoringLocation bool // 刷新配置后恢复原地点时，不重置勾选
+	credentialsWarned bool // 已提示过凭据文件无法读取（每次运行只提示一次）
+
 	// UI 组件
-	locationSelect *widget.Select
+	titleText      *canvas.Text
+	locationPicker *locationPicker
 	refreshBtn     *widget.Button
-	printerTable   *widget.List
+	searchEntry    *searchEntry
+	printerTable   *printerList
+	detailsPanel   *fyne.Container
 	selectAllBtn   *widget.Button
 	deselectAllBtn *widget.Button
 	installBtn     *widget.Button
 	statusLabel    *widget.Label
 	progressBar    *widget.ProgressBar
+	changeBanner   *fyne.Container // 配置变更提示条
+	bannerTitle    *widget.Label
+	bannerDetails  *widget.Label
 
 	// 数据绑定
 	statusText binding.String
@@ -332,19 +127,27 @@ type PrinterInstallerGUI struct {
 // NewPrinterInstallerGUI 创建新的安装程序界面
 func NewPrinterInstallerGUI() *PrinterInstallerGUI {
 	myApp := app.NewWithID("com.kylin.printer.installer")
+	initLocale(myApp.Preferences().String(prefLanguage))
 
-	// 设置自定义亮色主题（带中文字体）
-	myApp.Settings().SetTheme(newLightTheme())
+	// 设置自定义主题（带中文字体），字体需同时覆盖上次配置中的打印机名称
+	appTheme := newAppTheme(myApp.Preferences().String(prefFontSample)).withSettings(loadThemeSettings(myApp.Preferences()))
+	myApp.Settings().SetTheme(appTheme)
 
 	gui := &PrinterInstallerGUI{
-		app:          myApp,
-		configURL:    "http://10.245.93.86/printer/printer-config.json",
-		printerData:  make([]Printer, 0),
-		checkedItems: make(map[int]bool),
-		statusText:   binding.NewString(),
+		app:           myApp,
+		theme:         appTheme,
+		printerData:   make([]PrinterRow, 0),
+		checkedItems:  make(map[string]bool),
+		installStates: make(map[string]*installStatus),
+		statusText:    binding.NewString(),
+		backend:       newPrinterBackend(),
 	}
+	fmt.Printf("✓ 打印队列后端: %s\n", gui.backend.Name())
+	
+	gui.httpClient, gui.httpClientErr = newHTTPClient(loadNetworkSettings(myApp.Preferences()))
+	gui.configSources = loadConfigSourceList(myApp.Preferences())
 
-	gui.statusText.Set("就绪")
+	gui.statusText.Set(tr("status.ready"))
 
 	// 设置应用图标
 	gui.setAppIcon()
@@ -352,9 +155,19 @@ func NewPrinterInstallerGUI() *PrinterInstallerGUI {
 	return gui
 }
 
+// client 返回 HTTP 客户端；网络设置（证书等）有误时返回错误，不回退为默认客户端
+func (gui *PrinterInstallerGUI) client() (*http.Client, error) {
+	gui.mutex.Lock()
+	defer gui.mutex.Unlock()
+	if gui.httpClientErr != nil {
+		return nil, fmt.Errorf(tr("error.network_settings"), gui.httpClientErr)
+	}
+	return gui.httpClient, nil
+}
+
 // Run 运行应用程序
 func (gui *PrinterInstallerGUI) Run() {
-	gui.window = gui.app.NewWindow("麒麟系统打印机自动安装程序 v1.0.1")
+	gui.window = gui.app.NewWindow(tr("app.title", appVersion))
 	gui.window.SetMaster() // 设置为主窗口
 
 	// 初始化UI (SetContent)
@@ -367,8 +180,11 @@ func (gui *PrinterInstallerGUI) Run() {
 	// 居中显示
 	gui.window.CenterOnScreen()
 
-	// 延迟加载配置
-	go gui.loadConfig()
+	// 延迟加载配置，之后在后台定期检查更新
+	go func() {
+		gui.loadConfig()
+		gui.watchConfig()
+	}()
 
 	gui.window.ShowAndRun()
 }
@@ -413,63 +229,76 @@ func (gui *PrinterInstallerGUI) setAppIcon() {
 // initUI 初始化用户界面
 func (gui *PrinterInstallerGUI) initUI() {
 	// 1. 标题区域 (使用 canvas.Text 实现大字体)
-	titleText := canvas.NewText("麒麟系统打印机自动安装工具", kylinBlue)
-	titleText.TextSize = 28 // 大字体
-	titleText.TextStyle = fyne.TextStyle{Bold: true}
-	titleText.Alignment = fyne.TextAlignCenter
+	gui.titleText = canvas.NewText(tr("app.heading"), kylinBlue)
+	gui.titleText.TextSize = 2 * theme.TextSize() // 大字体，随文字缩放
+	gui.titleText.TextStyle = fyne.TextStyle{Bold: true}
+	gui.titleText.Alignment = fyne.TextAlignCenter
 	
 	headerBox := container.NewVBox(
-		container.NewCenter(titleText),
+		container.NewCenter(gui.titleText),
 		widget.NewSeparator(),
 	)
 	
 	// 2. 地点选择部分
-	locationLabel := widget.NewLabel("📍 选择安装地点:")
+	locationLabel := widget.NewLabel(tr("location.label"))
 	locationLabel.TextStyle = fyne.TextStyle{Bold: true}
 	
-	gui.locationSelect = widget.NewSelect([]string{}, gui.onLocationChanged)
-	gui.locationSelect.PlaceHolder = "请选择您的办公区域..."
+	gui.locationPicker = newLocationPicker(gui.onLocationChanged)
 	
-	gui.refreshBtn = widget.NewButtonWithIcon("刷新配置", theme.ViewRefreshIcon(), func() {
+	gui.refreshBtn = widget.NewButtonWithIcon(tr("button.refresh"), theme.ViewRefreshIcon(), func() {
 		go gui.loadConfig()
 	})
 	
+	settingsBtn := widget.NewButtonWithIcon(tr("button.settings"), theme.SettingsIcon(), gui.showSettings)
+	aboutBtn := widget.NewButtonWithIcon(tr("button.about"), theme.InfoIcon(), gui.showAbout)
+	
 	locationBox := container.NewBorder(
 		nil, nil,
 		locationLabel,
-		gui.refreshBtn,
-		gui.locationSelect,
+		container.NewHBox(gui.refreshBtn, settingsBtn, aboutBtn),
+		gui.locationPicker.Object(),
 	)
 	
+	// 搜索框：跨所有地点按名称、型号、IP、描述和标签过滤
+	gui.searchEntry = newSearchEntry()
+	gui.searchEntry.SetPlaceHolder(tr("search.placeholder"))
+	gui.searchEntry.OnChanged = func(string) {
+		gui.applyFilter()
+	}
+	
 	// 给地点选择加一个带边框的卡片效果
-	locationCard := widget.NewCard("", "", container.NewPadded(locationBox))
+	locationCard := widget.NewCard("", "", container.NewPadded(container.NewVBox(locationBox, gui.searchEntry)))
 	
-	// 3. 打印机列表（使用 List + 复选框）
-	gui.printerTable = widget.NewList(
+	// 3. 打印机列表（使用 List + 复选框，支持键盘操作，见 printerList）
+	gui.printerTable = newPrinterList(
 		func() int {
 			return len(gui.printerData)
 		},
 		func() fyne.CanvasObject {
 			// CreateItem: 创建列表项模板
-			check := widget.NewCheck("", nil)
-			check.Resize(fyne.NewSize(30, 20))
+			// 复选框的文字即打印机名称，可用 Tab 聚焦、空格勾选，点击名称也能勾选
+			check := widget.NewCheck(tr("list.template_name"), nil)
 			
-			// 使用 canvas.Text 可以设置颜色
-			nameText := canvas.NewText("打印机名称", headerColor)
-			nameText.TextSize = 16
-			nameText.TextStyle = fyne.TextStyle{Bold: true}
+			defaultCheck := widget.NewCheck(tr("list.set_default"), nil)
 			
-			modelLabel := widget.NewLabel("型号")
+			modelLabel := widget.NewLabel(tr("list.template_model"))
 			ipLabel := widget.NewLabel("IP")
+			locationLabel := widget.NewLabel(tr("list.template_location"))
+			summaryLabel := widget.NewLabel(tr("list.template_summary"))
 			
-			// 布局: [Check] [Name]
-			//               [Model] - [IP]
+			// 布局: [Check Name] [安装状态]
+			//       [Model] - [IP] [Location]
+			//       [Floor/Room  Capabilities]
+			//       [安装失败时的错误详情和重试按钮]
 			infoBox := container.NewVBox(
-				nameText,
-				container.NewHBox(modelLabel, widget.NewLabel("-"), ipLabel),
+				container.NewHBox(check, widget.NewIcon(nil), widget.NewLabel("")),
+				container.NewHBox(modelLabel, widget.NewLabel("-"), ipLabel, locationLabel),
+				summaryLabel,
+				newInstallErrorBox(),
 			)
 			
-			return container.NewHBox(check, infoBox)
+			// 布局: [Info] ... [设为默认]
+			return container.NewBorder(nil, nil, nil, defaultCheck, infoBox)
 		},
 		func(id widget.ListItemID, item fyne.CanvasObject) {
 			// UpdateItem: 更新数据
@@ -477,34 +306,24 @@ func (gui *PrinterInstallerGUI) initUI() {
 				return
 			}
 			
-			printer := gui.printerData[id]
+			row := gui.printerData[id]
+			printer := row.Printer
+			key := printerKey(row.Location, printer)
 			
-			// item 是 HBox
+			// item 是 Border: [0]Info, [1]设为默认
 			box := item.(*fyne.Container)
 			
-			// 1. 复选框
+			// 1. 信息区域
 			if len(box.Objects) > 0 {
-				if check, ok := box.Objects[0].(*widget.Check); ok {
-					check.Checked = gui.checkedItems[id]
-					check.OnChanged = func(checked bool) {
-						gui.mutex.Lock()
-						gui.checkedItems[id] = checked
-						gui.mutex.Unlock()
-						gui.updateInstallBtnState()
-					}
-					check.Refresh()
-				}
-			}
-			
-			// 2. 信息区域
-			if len(box.Objects) > 1 {
-				if infoBox, ok := box.Objects[1].(*fyne.Container); ok {
-					// infoBox [0]NameText, [1]DetailBox
-					if len(infoBox.Objects) > 0 {
-						if nameText, ok := infoBox.Objects[0].(*canvas.Text); ok {
-							nameText.Text = printer.Name
-							nameText.Refresh()
+				if infoBox, ok := box.Objects[0].(*fyne.Container); ok {
+					// infoBox [0]Check（名称）和安装状态, [1]DetailBox, [2]Summary, [3]错误详情
+					if check := rowParts(item).check; check != nil {
+						check.Text = printer.displayName()
+						check.Checked = gui.checkedItems[key]
+						check.OnChanged = func(checked bool) {
+							gui.setChecked(key, checked)
 						}
+						check.Refresh()
 					}
 					
 					if len(infoBox.Objects) > 1 {
@@ -515,19 +334,76 @@ func (gui *PrinterInstallerGUI) initUI() {
 							if len(detailBox.Objects) > 2 {
 								detailBox.Objects[2].(*widget.Label).SetText(printer.IP)
 							}
+							if len(detailBox.Objects) > 3 {
+								// 搜索结果来自多个地点，需要标明所属地点
+								locationLabel := detailBox.Objects[3].(*widget.Label)
+								if gui.searching {
+									locationLabel.SetText("📍 " + gui.config.locationLabel(row.Location))
+									locationLabel.Show()
+								} else {
+									locationLabel.Hide()
+								}
+							}
+						}
+					}
+					
+					if len(infoBox.Objects) > 2 {
+						if summaryLabel, ok := infoBox.Objects[2].(*widget.Label); ok {
+							summary := printer.summaryText()
+							if len(gui.configSources) > 1 && printer.Source != "" {
+								summary = strings.TrimSpace(summary + "  📄 " + sourceLabel(printer.Source))
+							}
+							if summary != "" {
+								summaryLabel.SetText(summary)
+								summaryLabel.Show()
+							} else {
+								summaryLabel.Hide()
+							}
 						}
 					}
 				}
 			}
+			
+			// 2. 设为默认（同一时间只能有一台）
+			if len(box.Objects) > 1 {
+				if defaultCheck, ok := box.Objects[1].(*widget.Check); ok {
+					defaultCheck.Checked = gui.defaultKey == key
+					defaultCheck.OnChanged = func(checked bool) {
+						gui.setDefaultKey(key, checked)
+					}
+					defaultCheck.Refresh()
+				}
+			}
+			
+			// 3. 安装状态（安装过程中和安装之后）
+			gui.showInstallStatus(rowParts(item), row)
 		},
 	)
 	
-	printerCard := widget.NewCard("可用打印机", "", gui.printerTable)
+	// 点击列表行时在右侧显示详情
+	gui.printerTable.OnSelected = func(id widget.ListItemID) {
+		gui.mutex.Lock()
+		if id >= len(gui.printerData) {
+			gui.mutex.Unlock()
+			return
+		}
+		row := gui.printerData[id]
+		gui.mutex.Unlock()
+		gui.printerTable.setFocus(id)
+		gui.showDetails(row)
+	}
+	
+	gui.detailsPanel = container.NewStack(newDetailsPlaceholder())
+	
+	printerSplit := container.NewHSplit(gui.printerTable, gui.detailsPanel)
+	printerSplit.Offset = 0.62
+	
+	printerCard := widget.NewCard(tr("card.printers"), "", printerSplit)
 
 	
 	// 4. 全选/全不选按钮
-	gui.selectAllBtn = widget.NewButton("全选", gui.selectAll)
-	gui.deselectAllBtn = widget.NewButton("全不选", gui.deselectAll)
+	gui.selectAllBtn = widget.NewButton(tr("button.select_all"), gui.selectAll)
+	gui.deselectAllBtn = widget.NewButton(tr("button.deselect_all"), gui.deselectAll)
 	
 	selectBtnBox := container.NewHBox(
 		gui.selectAllBtn,
@@ -542,11 +418,11 @@ func (gui *PrinterInstallerGUI) initUI() {
 	gui.statusLabel = widget.NewLabel("")
 	gui.statusLabel.Bind(gui.statusText)
 	
-	gui.installBtn = widget.NewButtonWithIcon("安装选中的打印机", theme.ConfirmIcon(), gui.installPrinters)
+	gui.installBtn = widget.NewButtonWithIcon(tr("button.install"), theme.ConfirmIcon(), gui.installPrinters)
 	gui.installBtn.Importance = widget.HighImportance
 	gui.installBtn.Disable()
 	
-	exitBtn := widget.NewButton("退出", func() {
+	exitBtn := widget.NewButton(tr("button.exit"), func() {
 		gui.app.Quit()
 	})
 	
@@ -560,6 +436,7 @@ func (gui *PrinterInstallerGUI) initUI() {
 	content := container.NewBorder(
 		container.NewVBox(
 			headerBox,
+			gui.newChangeBanner(),
 			locationCard,
 		),
 		container.NewVBox(
@@ -572,41 +449,99 @@ func (gui *PrinterInstallerGUI) initUI() {
 	)
 	
 	gui.window.SetContent(content)
+	gui.addShortcuts()
 }
 
 // loadConfig 从服务器加载配置文件
 func (gui *PrinterInstallerGUI) loadConfig() {
-	gui.statusText.Set("正在加载配置文件...")
+	gui.statusText.Set(tr("status.loading_config"))
 	gui.refreshBtn.Disable()
 	
-	resp, err := http.Get(gui.configURL)
+	client, err := gui.client()
 	if err != nil {
 		gui.refreshBtn.Enable()
-		gui.statusText.Set("配置加载失败")
-		dialog.ShowError(fmt.Errorf("无法加载配置: %v", err), gui.window)
+		gui.statusText.Set(tr("status.config_failed"))
+		dialog.ShowError(err, gui.window)
 		return
 	}
-	defer resp.Body.Close()
 	
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
+	sources, ok := gui.beginLoad()
+	if !ok {
 		gui.refreshBtn.Enable()
-		gui.statusText.Set("配置加载失败")
-		dialog.ShowError(fmt.Errorf("读取配置失败: %v", err), gui.window)
+		gui.statusText.Set(tr("status.busy"))
 		return
 	}
+	defer gui.endLoad()
 	
-	var config PrinterConfig
-	if err := json.Unmarshal(body, &config); err != nil {
+	config, err := loadConfigSources(client, sources)
+	if err != nil {
 		gui.refreshBtn.Enable()
-		gui.statusText.Set("配置加载失败")
-		dialog.ShowError(fmt.Errorf("解析配置失败: %v", err), gui.window)
+		var updateErr *updateRequiredError
+		if errors.As(err, &updateErr) {
+			gui.statusText.Set(tr("status.update_required"))
+			dialog.ShowInformation(tr("dialog.update_required"), err.Error(), gui.window)
+			return
+		}
+		gui.statusText.Set(tr("status.config_failed"))
+		// 服务器要求认证时，凭据文件无法读取往往就是原因
+		gui.showCredentialWarnings(client)
+		dialog.ShowError(err, gui.window)
 		return
 	}
 	
-	gui.config = &config
-	gui.updateLocations()
+	// 重新加载时保留当前地点和勾选，并提示变化
+	if !gui.applyConfig(config) {
+		// 手动刷新时告知没有变化（后台刷新不改动状态栏）
+		gui.statusText.Set(tr("status.config_unchanged"))
+	}
 	gui.refreshBtn.Enable()
+	
+	// 校验配置（含多个来源合并时的冲突），有问题的打印机在安装时会被拒绝
+	gui.showCredentialWarnings(client)
+	if issues := validateConfig(gui.config); len(issues) > 0 {
+		gui.showConfigIssues(issues)
+	}
+}
+
+// updateFontCoverage 配置中出现当前字体缺少的字符时，重新选择覆盖更好的字体
+func (gui *PrinterInstallerGUI) updateFontCoverage(config *PrinterConfig) {
+	prefs := gui.app.Preferences()
+	sample := configSampleText(config)
+	if sample == prefs.String(prefFontSample) {
+		return
+	}
+	prefs.SetString(prefFontSample, sample)
+	
+	if gui.theme.regular == nil {
+		return // 没有可用的中文字体，重新查找也不会有结果
+	}
+	if missing, err := missingGlyphs(gui.theme.regular.Content(), sample); err == nil && len(missing) == 0 {
+		return
+	}
+	
+	newTheme := newAppTheme(sample).withSettings(gui.theme.settings)
+	if newTheme.report.Path != gui.theme.report.Path {
+		fmt.Printf("✓ 打印机名称中有当前字体缺少的字符，改用: %s\n", newTheme.report.Path)
+		gui.theme = newTheme
+		gui.app.Settings().SetTheme(newTheme)
+	} else {
+		gui.theme.report = newTheme.report
+	}
+}
+
+// showConfigIssues 显示配置校验发现的问题
+func (gui *PrinterInstallerGUI) showConfigIssues(issues []string) {
+	issueLabel := widget.NewLabel(strings.Join(issues, "\n"))
+	issueLabel.Wrapping = fyne.TextWrapWord
+	
+	scroll := container.NewVScroll(issueLabel)
+	scroll.SetMinSize(fyne.NewSize(520, 220))
+	
+	content := container.NewVBox(
+		widget.NewLabel(trn("issues.heading", len(issues))),
+		scroll,
+	)
+	dialog.ShowCustom(tr("issues.title"), tr("button.close"), content, gui.window)
 }
 
 // updateLocations 更新地点列表
@@ -616,48 +551,129 @@ func (gui *PrinterInstallerGUI) updateLocations() {
 	}
 	
 	locations := make([]string, 0, len(gui.config.Locations))
-	for location := range gui.config.Locations {
-		locations = append(locations, location)
+	for location, printers := range gui.config.Locations {
+		if len(printers) > 0 {
+			locations = append(locations, location)
+		}
 	}
 	sort.Strings(locations)
 	
-	gui.locationSelect.Options = locations
+	gui.locationPicker.SetTree(gui.config.locationTree)
 	
 	if len(locations) > 0 {
-		gui.locationSelect.SetSelected(locations[0])
-		gui.statusText.Set(fmt.Sprintf("配置加载成功 - 共 %d 个地点", len(locations)))
+		gui.locationPicker.SetSelected(gui.preferredLocation(locations))
+		gui.statusText.Set(trn("status.config_loaded", len(locations)))
 		
 		// 移除成功弹窗，避免打扰用户
 		// dialog.ShowInformation("成功", "配置文件加载成功", gui.window)
 	} else {
-		gui.statusText.Set("配置加载成功 - 但没有地点数据")
-		dialog.ShowInformation("警告", "配置文件中没有找到任何地点信息", gui.window)
+		gui.statusText.Set(tr("status.config_no_locations"))
+		dialog.ShowInformation(tr("dialog.warning"), tr("dialog.no_locations"), gui.window)
 	}
 }
 
+// preferredLocation 选择默认地点
+// 优先级：按本机 IP/主机名自动识别 > 上次选择的地点 > 排序后的第一个地点
+func (gui *PrinterInstallerGUI) preferredLocation(locations []string) string {
+	if detected := detectLocation(gui.config); detected != "" {
+		fmt.Printf("✓ 自动识别地点: %s\n", detected)
+		return detected
+	}
+	
+	last := gui.app.Preferences().String(prefLastLocation)
+	for _, location := range locations {
+		if location == last {
+			return last
+		}
+	}
+	
+	return locations[0]
+}
+
 // onLocationChanged 地点选择变化时更新打印机列表
-func (gui *PrinterInstallerGUI) onLocationChanged(location string) {
-	if location == "" || gui.config == nil {
+// 只有用户手动选择的地点才记为上次选择，自动识别或加载配置时选中的地点不记录
+func (gui *PrinterInstallerGUI) onLocationChanged(location string, byUser bool) {
+	gui.mutex.Lock()
+	if location == "" || gui.config == nil || gui.restoringLocation {
+		gui.mutex.Unlock()
 		return
 	}
+	gui.location = location
+	gui.checkedItems = make(map[string]bool)
+	gui.defaultKey = ""
+	for _, printer := range gui.config.Locations[location] {
+		if printer.Default {
+			gui.defaultKey = printerKey(location, printer)
+			break
+		}
+	}
+	gui.mutex.Unlock()
+	
+	if byUser {
+		gui.app.Preferences().SetString(prefLastLocation, location)
+	}
+	
+	// 切换地点时退出搜索模式
+	if gui.searchEntry.Text != "" {
+		gui.searchEntry.SetText("")
+	} else {
+		gui.applyFilter()
+	}
+}
+
+// applyFilter 根据搜索框内容刷新打印机列表
+// 搜索框为空时显示当前地点的打印机，否则显示所有地点中匹配的打印机
+func (gui *PrinterInstallerGUI) applyFilter() {
+	query := strings.TrimSpace(gui.searchEntry.Text)
 	
 	gui.mutex.Lock()
-	gui.printerData = gui.config.Locations[location]
-	gui.checkedItems = make(map[int]bool)
+	gui.searching = query != ""
+	if gui.searching {
+		gui.printerData = searchPrinters(gui.config, query)
+	} else {
+		gui.printerData = locationRows(gui.config, gui.location)
+	}
+	count := len(gui.printerData)
+	searching := gui.searching
 	gui.mutex.Unlock()
 	
+	gui.printerTable.UnselectAll()
+	gui.printerTable.resetFocus()
+	gui.detailsPanel.Objects = []fyne.CanvasObject{newDetailsPlaceholder()}
+	gui.detailsPanel.Refresh()
+	gui.updateRowHeights()
 	gui.printerTable.Refresh()
 	gui.updateInstallBtnState()
-	gui.statusText.Set(fmt.Sprintf("已加载 %d 台打印机", len(gui.printerData)))
+	if searching {
+		gui.statusText.Set(trn("status.search_results", count))
+	} else {
+		gui.statusText.Set(trn("status.printers_loaded", count))
+	}
 }
 
 
 
+// setDefaultKey 设置或取消安装后的默认打印机
+// 选为默认的打印机会同时被勾选安装
+func (gui *PrinterInstallerGUI) setDefaultKey(key string, isDefault bool) {
+	gui.mutex.Lock()
+	if isDefault {
+		gui.defaultKey = key
+		gui.checkedItems[key] = true
+	} else if gui.defaultKey == key {
+		gui.defaultKey = ""
+	}
+	gui.mutex.Unlock()
+	
+	gui.printerTable.Refresh()
+	gui.updateInstallBtnState()
+}
+
 // selectAll 全选
 func (gui *PrinterInstallerGUI) selectAll() {
 	gui.mutex.Lock()
-	for i := range gui.printerData {
-		gui.checkedItems[i] = true
+	for _, row := range gui.printerData {
+		gui.checkedItems[printerKey(row.Location, row.Printer)] = true
 	}
 	gui.mutex.Unlock()
 	
@@ -668,7 +684,7 @@ func (gui *PrinterInstallerGUI) selectAll() {
 // deselectAll 全不选
 func (gui *PrinterInstallerGUI) deselectAll() {
 	gui.mutex.Lock()
-	gui.checkedItems = make(map[int]bool)
+	gui.checkedItems = make(map[string]bool)
 	gui.mutex.Unlock()
 	
 	gui.printerTable.Refresh()
@@ -686,10 +702,10 @@ func (gui *PrinterInstallerGUI) updateInstallBtnState() {
 	
 	if count > 0 {
 		gui.installBtn.Enable()
-		gui.installBtn.SetText(fmt.Sprintf("安装选中的打印机 (%d)", count))
+		gui.installBtn.SetText(tr("button.install_count", count))
 	} else {
 		gui.installBtn.Disable()
-		gui.installBtn.SetText("安装选中的打印机")
+		gui.installBtn.SetText(tr("button.install"))
 	}
 }
 
@@ -703,13 +719,13 @@ func (gui *PrinterInstallerGUI) showCustomConfirm(title, message string, callbac
 	// 创建按钮
 	var confirmDialog *dialog.CustomDialog
 	
-	confirmBtn := widget.NewButton("确定", func() {
+	confirmBtn := widget.NewButton(tr("button.ok"), func() {
 		confirmDialog.Hide()
 		callback(true)
 	})
 	confirmBtn.Importance = widget.HighImportance
 	
-	cancelBtn := widget.NewButton("取消", func() {
+	cancelBtn := widget.NewButton(tr("button.cancel"), func() {
 		confirmDialog.Hide()
 		callback(false)
 	})
@@ -739,14 +755,9 @@ func (gui *PrinterInstallerGUI) showCustomConfirm(title, message string, callbac
 
 // installPrinters 安装选中的打印机
 func (gui *PrinterInstallerGUI) installPrinters() {
-	selectedPrinters := make([]Printer, 0)
-	
+	// 勾选项可能来自搜索结果中的多个地点
 	gui.mutex.Lock()
-	for i, checked := range gui.checkedItems {
-		if checked && i < len(gui.printerData) {
-			selectedPrinters = append(selectedPrinters, gui.printerData[i])
-		}
-	}
+	selectedPrinters := checkedRows(gui.config, gui.checkedItems)
 	gui.mutex.Unlock()
 	
 	if len(selectedPrinters) == 0 {
@@ -754,8 +765,8 @@ func (gui *PrinterInstallerGUI) installPrinters() {
 	}
 	
 	// 使用自定义确认对话框
-	confirmMsg := fmt.Sprintf("确定要安装 %d 台打印机吗?", len(selectedPrinters))
-	gui.showCustomConfirm("确认安装", confirmMsg, func(confirmed bool) {
+	confirmMsg := trn("install.confirm", len(selectedPrinters))
+	gui.showCustomConfirm(tr("install.confirm_title"), confirmMsg, func(confirmed bool) {
 		if confirmed {
 			go gui.installProcess(selectedPrinters)
 		}
@@ -763,64 +774,105 @@ func (gui *PrinterInstallerGUI) installPrinters() {
 }
 
 // installProcess 安装过程
-func (gui *PrinterInstallerGUI) installProcess(printers []Printer) {
+func (gui *PrinterInstallerGUI) installProcess(printers []PrinterRow) {
+	config, defaultKey, ok := gui.beginInstall()
+	if !ok {
+		gui.statusText.Set(tr("status.busy"))
+		return
+	}
+	
 	// 显示进度条
 	gui.progressBar.Show()
 	gui.progressBar.Max = float64(len(printers))
 	gui.progressBar.SetValue(0)
 	gui.installBtn.Disable()
 	
+	// 列表中先把全部待安装的打印机标记为等待
+	for _, row := range printers {
+		gui.setInstallState(printerKey(row.Location, row.Printer), installPending, "")
+	}
+	gui.printerTable.Refresh()
+	
 	successCount := 0
-	failedPrinters := make([]string, 0)
+	failedCount := 0
+	defaultPrinter := ""
 	
-	for i, printer := range printers {
+	for i, row := range printers {
+		printer := row.Printer
+		key := printerKey(row.Location, printer)
+		
 		// 更新进度
-		gui.statusText.Set(fmt.Sprintf("正在安装: %s...", printer.Name))
+		gui.statusText.Set(tr("install.status_installing", printer.displayName()))
 		gui.progressBar.SetValue(float64(i))
 		
-		success, errMsg := gui.installSinglePrinter(printer)
+		success, errMsg := gui.installSinglePrinter(config, row)
 		if success {
 			successCount++
+			gui.setInstallState(key, installDone, "")
+			if key == defaultKey {
+				defaultPrinter = printer.queueName()
+			}
 		} else {
-			failedPrinters = append(failedPrinters, fmt.Sprintf("%s: %s", printer.Name, errMsg))
+			failedCount++
+			gui.setInstallState(key, installFailed, errMsg)
 		}
 	}
 	
+	// 设置默认打印机（仅当选为默认的打印机安装成功时）
+	defaultMsg := ""
+	if defaultPrinter != "" {
+		defaultMsg = "\n\n" + gui.setDefaultPrinter(defaultPrinter)
+	}
+	
 	// 完成
+	gui.mutex.Lock()
+	gui.installing = false
+	gui.mutex.Unlock()
 	gui.progressBar.Hide()
 	gui.updateInstallBtnState()
-	gui.statusText.Set(fmt.Sprintf("安装完成 - 成功: %d, 失败: %d", successCount, len(failedPrinters)))
-	
-	// 显示结果
-	resultMsg := fmt.Sprintf("安装完成!\n\n成功: %d 台\n失败: %d 台", successCount, len(failedPrinters))
-	if len(failedPrinters) > 0 {
-		resultMsg += "\n\n失败详情:\n"
-		displayCount := len(failedPrinters)
-		if displayCount > 5 {
-			displayCount = 5
-		}
-		resultMsg += strings.Join(failedPrinters[:displayCount], "\n")
-		if len(failedPrinters) > 5 {
-			resultMsg += fmt.Sprintf("\n... 还有 %d 台", len(failedPrinters)-5)
-		}
-		dialog.ShowInformation("安装结果", resultMsg, gui.window)
-	} else {
-		dialog.ShowInformation("安装结果", resultMsg, gui.window)
+	gui.printerTable.Refresh()
+	gui.statusText.Set(tr("install.status_done", trn("install.succeeded", successCount), trn("install.failed", failedCount)))
+	
+	// 显示结果，失败原因在列表中对应的行内查看和重试
+	resultMsg := tr("install.result_heading") + "\n\n" +
+		trn("install.result_succeeded", successCount) + "\n" +
+		trn("install.result_failed", failedCount) + defaultMsg
+	if failedCount > 0 {
+		resultMsg += "\n\n" + tr("install.failures_in_list")
+	}
+	dialog.ShowInformation(tr("install.result_title"), resultMsg, gui.window)
+}
+
+// setDefaultPrinter 设置默认打印机，返回结果说明
+func (gui *PrinterInstallerGUI) setDefaultPrinter(name string) string {
+	gui.statusText.Set(tr("install.status_default", name))
+	if err := gui.backend.SetDefaultPrinter(name); err != nil {
+		return tr("install.default_failed", err)
 	}
+	return tr("install.default_set", name)
 }
 
-// installSinglePrinter 安装单台打印机
-func (gui *PrinterInstallerGUI) installSinglePrinter(printer Printer) (bool, string) {
+// installSinglePrinter 安装单台打印机，config 为开始安装时的配置（安装期间不会被刷新替换）
+func (gui *PrinterInstallerGUI) installSinglePrinter(config *PrinterConfig, row PrinterRow) (bool, string) {
+	printer := row.Printer
+	key := printerKey(row.Location, printer)
+	
+	// 配置来自远程服务器，传给 lpadmin 之前必须校验
+	queue := newPrinterQueue(row)
+	if err := validatePrinter(row); err != nil {
+		return false, tr("install.error_invalid", err)
+	}
+	
 	// 获取 PPD URL
 	ppdURL := ""
-	if gui.config != nil {
-		if modelInfo, ok := gui.config.PrinterModels[printer.Model]; ok {
+	if config != nil {
+		if modelInfo, ok := config.PrinterModels[printer.Model]; ok {
 			ppdURL = modelInfo.PPDURL
 		}
 	}
 	
 	if ppdURL == "" {
-		return false, fmt.Sprintf("配置文件中未找到型号 '%s' 的ppd_url，请在服务器的printer_config.json中配置", printer.Model)
+		return false, tr("install.error_no_ppd", printer.Model)
 	}
 	
 	// 对URL中的非ASCII字符进行编码
@@ -832,68 +884,65 @@ func (gui *PrinterInstallerGUI) installSinglePrinter(printer Printer) (bool, str
 	}
 	
 	// 下载 PPD 文件
+	gui.setInstallState(key, installDownloading, "")
 	tempFile, err := os.CreateTemp("", "printer-*.ppd")
 	if err != nil {
-		return false, fmt.Sprintf("创建临时文件失败: %v", err)
+		return false, tr("install.error_temp_file", err)
 	}
 	tempPPDPath := tempFile.Name()
 	tempFile.Close()
 	defer os.Remove(tempPPDPath)
 	
-	resp, err := http.Get(ppdURL)
+	client, err := gui.client()
+	if err != nil {
+		return false, err.Error()
+	}
+	
+	resp, err := client.Get(ppdURL)
 	if err != nil {
-		return false, fmt.Sprintf("下载PPD文件失败 (%s): %v", ppdURL, err)
+		return false, tr("install.error_download_url", ppdURL, err)
 	}
 	defer resp.Body.Close()
 	
+	if err := checkResponse(resp); err != nil {
+		return false, tr("install.error_download", err)
+	}
+	
 	outFile, err := os.Create(tempPPDPath)
 	if err != nil {
-		return false, fmt.Sprintf("创建PPD文件失败: %v", err)
+		return false, tr("install.error_create_ppd", err)
 	}
 	
 	_, err = io.Copy(outFile, resp.Body)
 	outFile.Close()
 	if err != nil {
-		return false, fmt.Sprintf("保存PPD文件失败: %v", err)
-	}
-	
-	// 检查打印机是否已存在
-	checkCmd := exec.Command("lpstat", "-p", printer.Name)
-	if err := checkCmd.Run(); err == nil {
-		// 打印机已存在，先删除
-		deleteCmd := exec.Command("lpadmin", "-x", printer.Name)
-		deleteCmd.Run()
+		return false, tr("install.error_save_ppd", err)
 	}
 	
-	// 设置打印机 URI
-	printerURI := printer.URI
-	if printerURI == "" {
-		printerURI = fmt.Sprintf("ipp://%s/ipp/print", printer.IP)
-	}
-	
-	// 安装打印机
-	installCmd := exec.Command(
-		"lpadmin",
-		"-p", printer.Name,
-		"-v", printerURI,
-		"-P", tempPPDPath,
-		"-E",
-		"-D", fmt.Sprintf("%s (%s)", printer.Name, printer.Model),
-	)
-	
-	output, err := installCmd.CombinedOutput()
-	if err != nil {
-		errMsg := string(output)
-		if errMsg == "" {
-			errMsg = "未知错误"
-		}
-		return false, errMsg
+	// 安装打印机（已存在的同名打印机会被替换）
+	gui.setInstallState(key, installConfiguring, "")
+	if err := gui.backend.AddPrinter(queue, tempPPDPath); err != nil {
+		return false, err.Error()
 	}
+	removeLegacyQueue(gui.backend, printer, queue)
 	
 	return true, ""
 }
 
 func main() {
+	// 命令行子命令，不创建图形界面
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "helper":
+			// 特权助手模式：由 D-Bus 以 root 身份启动
+			os.Exit(runHelper(os.Args[2:]))
+		case "convert-config":
+			os.Exit(runConvertConfig(os.Args[2:]))
+		case "diagnose":
+			os.Exit(runDiagnose(os.Args[2:]))
+		}
+	}
+	
 	// 捕获 Panic 并写入日志文件
 	defer func() {
 		if r := recover(); r != nil {
@@ -0,0 +1,207 @@
+package main
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"golang.org/x/net/http/httpproxy"
+)
+
+// 网络设置在 Fyne Preferences 中的键名
+const (
+	prefTLSCAFile     = "tls_ca_file"     // 额外信任的 CA 证书（PEM）
+	prefTLSClientCert = "tls_client_cert" // 客户端证书（PEM）
+	prefTLSClientKey  = "tls_client_key"  // 客户端私钥（PEM）
+	prefTLSPinSHA256  = "tls_pin_sha256"  // 服务器证书 SHA-256 指纹
+	prefProxyMode     = "proxy_mode"      // 代理模式，见 proxyMode* 常量
+	prefProxyURL      = "proxy_url"       // 手动代理地址
+	prefNoProxy       = "no_proxy"        // 不经过代理、直接访问的主机（逗号分隔）
+)
+
+// 代理模式
+const (
+	proxyModeSystem = "system" // 使用 HTTP_PROXY/HTTPS_PROXY/NO_PROXY 环境变量（默认）
+	proxyModeManual = "manual" // 使用手动设置的代理
+	proxyModeNone   = "none"   // 不使用代理
+)
+
+// httpTimeout 配置和 PPD 下载的超时时间
+const httpTimeout = 60 * time.Second
+
+// NetworkSettings 配置和 PPD 下载共用的网络设置
+type NetworkSettings struct {
+	CAFile       string // 额外信任的 CA 证书包路径
+	ClientCert   string // 客户端证书路径（需与 ClientKey 同时设置）
+	ClientKey    string // 客户端私钥路径
+	PinnedSHA256 string // 服务器证书指纹（十六进制，可带冒号）
+
+	ProxyMode string // 代理模式：system / manual / none
+	ProxyURL  string // 手动代理地址，如 http://proxy.example.com:3128
+	NoProxy   string // 直接访问的主机，格式同 NO_PROXY 环境变量
+}
+
+// loadNetworkSettings 从 Preferences 读取网络设置
+func loadNetworkSettings(prefs fyne.Preferences) NetworkSettings {
+	return NetworkSettings{
+		CAFile:       strings.TrimSpace(prefs.String(prefTLSCAFile)),
+		ClientCert:   strings.TrimSpace(prefs.String(prefTLSClientCert)),
+		ClientKey:    strings.TrimSpace(prefs.String(prefTLSClientKey)),
+		PinnedSHA256: strings.TrimSpace(prefs.String(prefTLSPinSHA256)),
+		ProxyMode:    prefs.StringWithFallback(prefProxyMode, proxyModeSystem),
+		ProxyURL:     strings.TrimSpace(prefs.String(prefProxyURL)),
+		NoProxy:      strings.TrimSpace(prefs.String(prefNoProxy)),
+	}
+}
+
+// save 将网络设置写入 Preferences
+func (s NetworkSettings) save(prefs fyne.Preferences) {
+	prefs.SetString(prefTLSCAFile, s.CAFile)
+	prefs.SetString(prefTLSClientCert, s.ClientCert)
+	prefs.SetString(prefTLSClientKey, s.ClientKey)
+	prefs.SetString(prefTLSPinSHA256, s.PinnedSHA256)
+	prefs.SetString(prefProxyMode, s.ProxyMode)
+	prefs.SetString(prefProxyURL, s.ProxyURL)
+	prefs.SetString(prefNoProxy, s.NoProxy)
+}
+
+// newHTTPClient 根据网络设置创建 HTTP 客户端
+func newHTTPClient(s NetworkSettings) (*http.Client, error) {
+	tlsConfig, err := newTLSConfig(s)
+	if err != nil {
+		return nil, err
+	}
+
+	proxy, err := newProxyFunc(s)
+	if err != nil {
+		return nil, err
+	}
+
+	credentials, err := loadCredentials()
+	if err != nil {
+		return nil, err
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = tlsConfig
+	transport.Proxy = proxy
+
+	return &http.Client{
+		Transport: &authTransport{base: transport, store: credentials},
+		Timeout:   httpTimeout,
+	}, nil
+}
+
+// newProxyFunc 根据代理模式返回 Transport.Proxy
+// NoProxy 中的主机在任何模式下都直接访问（格式同 NO_PROXY：域名、.后缀、IP、CIDR）
+func newProxyFunc(s NetworkSettings) (func(*http.Request) (*url.URL, error), error) {
+	var cfg *httpproxy.Config
+	switch s.ProxyMode {
+	case "", proxyModeSystem:
+		cfg = httpproxy.FromEnvironment()
+		if s.NoProxy != "" {
+			cfg.NoProxy = strings.Trim(cfg.NoProxy+","+s.NoProxy, ",")
+		}
+	case proxyModeManual:
+		proxyURL, err := url.Parse(s.ProxyURL)
+		if err != nil || proxyURL.Host == "" {
+			return nil, fmt.Errorf(tr("error.proxy_url"), s.ProxyURL)
+		}
+		switch proxyURL.Scheme {
+		case "http", "https", "socks5":
+		default:
+			return nil, fmt.Errorf(tr("error.proxy_scheme"), proxyURL.Scheme)
+		}
+		cfg = &httpproxy.Config{
+			HTTPProxy:  s.ProxyURL,
+			HTTPSProxy: s.ProxyURL,
+			NoProxy:    s.NoProxy,
+		}
+	case proxyModeNone:
+		return nil, nil
+	default:
+		return nil, fmt.Errorf(tr("error.proxy_mode"), s.ProxyMode)
+	}
+
+	proxyFunc := cfg.ProxyFunc()
+	return func(req *http.Request) (*url.URL, error) {
+		return proxyFunc(req.URL)
+	}, nil
+}
+
+// newTLSConfig 创建 TLS 配置：系统 CA + 自定义 CA、可选客户端证书、可选证书指纹校验
+// 只设置指纹而不设置 CA 时，只按指纹校验服务器证书（适用于自签名证书）
+func newTLSConfig(s NetworkSettings) (*tls.Config, error) {
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+
+	if s.CAFile != "" {
+		pool, err := x509.SystemCertPool()
+		if err != nil || pool == nil {
+			pool = x509.NewCertPool()
+		}
+		pem, err := os.ReadFile(s.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf(tr("error.ca_read"), err)
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf(tr("error.ca_pem"), s.CAFile)
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	if s.ClientCert != "" || s.ClientKey != "" {
+		if s.ClientCert == "" || s.ClientKey == "" {
+			return nil, errors.New(tr("error.client_cert_pair"))
+		}
+		cert, err := tls.LoadX509KeyPair(s.ClientCert, s.ClientKey)
+		if err != nil {
+			return nil, fmt.Errorf(tr("error.client_cert"), err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	if s.PinnedSHA256 != "" {
+		pin, err := parseFingerprint(s.PinnedSHA256)
+		if err != nil {
+			return nil, err
+		}
+		// 设置了 CA 时，先做正常的证书链校验，再额外校验指纹；
+		// 只设置指纹时（如服务器使用自签名证书）以指纹代替证书链和主机名校验，
+		// 指纹唯一确定服务器证书，否则自签名证书会在证书链校验时就被拒绝
+		if s.CAFile == "" {
+			tlsConfig.InsecureSkipVerify = true
+		}
+		tlsConfig.VerifyConnection = func(cs tls.ConnectionState) error {
+			if len(cs.PeerCertificates) == 0 {
+				return errors.New(tr("error.no_server_cert"))
+			}
+			sum := sha256.Sum256(cs.PeerCertificates[0].Raw)
+			if !bytes.Equal(sum[:], pin) {
+				return fmt.Errorf(tr("error.pin_mismatch"), hex.EncodeToString(sum[:]))
+			}
+			return nil
+		}
+	}
+
+	return tlsConfig, nil
+}
+
+// parseFingerprint 解析 SHA-256 指纹，支持 "AB:CD:..." 和纯十六进制两种写法
+func parseFingerprint(s string) ([]byte, error) {
+	cleaned := strings.NewReplacer(":", "", " ", "").Replace(strings.ToLower(s))
+	pin, err := hex.DecodeString(cleaned)
+	if err != nil || len(pin) != sha256.Size {
+		return nil, fmt.Errorf(tr("error.pin_format"), s)
+	}
+	return pin, nil
+}
@@ -0,0 +1,164 @@
+package main
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/hex"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// writePEM 把 PEM 块写入临时目录中的文件，返回路径
+func writePEM(t *testing.T, name, blockType string, der []byte) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	data := pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der})
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// serverCAFile 把测试服务器的自签名证书写成 CA 文件
+func serverCAFile(t *testing.T, server *httptest.Server) string {
+	return writePEM(t, "ca.pem", "CERTIFICATE", server.Certificate().Raw)
+}
+
+// serverPin 测试服务器证书的 SHA-256 指纹（带冒号的大写写法，与设置界面一致）
+func serverPin(server *httptest.Server) string {
+	sum := sha256.Sum256(server.Certificate().Raw)
+	parts := make([]string, len(sum))
+	for i, b := range sum {
+		parts[i] = strings.ToUpper(hex.EncodeToString([]byte{b}))
+	}
+	return strings.Join(parts, ":")
+}
+
+// tlsGet 使用由网络设置生成的 TLS 配置请求测试服务器
+func tlsGet(t *testing.T, server *httptest.Server, s NetworkSettings) error {
+	t.Helper()
+	tlsConfig, err := newTLSConfig(s)
+	if err != nil {
+		t.Fatalf("newTLSConfig: %v", err)
+	}
+	client := &http.Client{
+		Transport: &http.Transport{TLSClientConfig: tlsConfig},
+		Timeout:   10 * time.Second,
+	}
+	resp, err := client.Get(server.URL)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return &httpStatusError{URL: server.URL, StatusCode: resp.StatusCode}
+	}
+	return nil
+}
+
+func newTestTLSServer() *httptest.Server {
+	return httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+}
+
+func TestTLSConfigCAFile(t *testing.T) {
+	server := newTestTLSServer()
+	defer server.Close()
+
+	if err := tlsGet(t, server, NetworkSettings{}); err == nil {
+		t.Error("未配置 CA 时不应信任自签名证书")
+	}
+	if err := tlsGet(t, server, NetworkSettings{CAFile: serverCAFile(t, server)}); err != nil {
+		t.Errorf("配置 CA 后请求失败: %v", err)
+	}
+}
+
+func TestTLSConfigPin(t *testing.T) {
+	server := newTestTLSServer()
+	defer server.Close()
+
+	caFile := serverCAFile(t, server)
+	pin := serverPin(server)
+	// httptest 的服务器都使用同一张内置证书，不匹配的指纹直接构造
+	wrongPin := strings.Repeat("00", sha256.Size)
+
+	// 只设置指纹：自签名证书按指纹校验
+	if err := tlsGet(t, server, NetworkSettings{PinnedSHA256: pin}); err != nil {
+		t.Errorf("只设置指纹时请求失败: %v", err)
+	}
+	// CA 和指纹同时设置
+	if err := tlsGet(t, server, NetworkSettings{CAFile: caFile, PinnedSHA256: pin}); err != nil {
+		t.Errorf("CA 和指纹都匹配时请求失败: %v", err)
+	}
+	// 指纹不匹配
+	if err := tlsGet(t, server, NetworkSettings{PinnedSHA256: wrongPin}); err == nil {
+		t.Error("指纹不匹配时应拒绝连接")
+	}
+	if err := tlsGet(t, server, NetworkSettings{CAFile: caFile, PinnedSHA256: wrongPin}); err == nil {
+		t.Error("证书链有效但指纹不匹配时应拒绝连接")
+	}
+}
+
+func TestTLSConfigClientCert(t *testing.T) {
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.TLS == nil || len(r.TLS.PeerCertificates) == 0 {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	server.TLS = &tls.Config{ClientAuth: tls.RequireAnyClientCert}
+	server.StartTLS()
+	defer server.Close()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "printer-installer"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	certDER, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	caFile := serverCAFile(t, server)
+	if err := tlsGet(t, server, NetworkSettings{CAFile: caFile}); err == nil {
+		t.Error("服务器要求客户端证书时，未配置证书的请求应失败")
+	}
+
+	settings := NetworkSettings{
+		CAFile:     caFile,
+		ClientCert: writePEM(t, "client.pem", "CERTIFICATE", certDER),
+		ClientKey:  writePEM(t, "client.key", "EC PRIVATE KEY", keyDER),
+	}
+	if err := tlsGet(t, server, settings); err != nil {
+		t.Errorf("配置客户端证书后请求失败: %v", err)
+	}
+
+	if _, err := newTLSConfig(NetworkSettings{ClientCert: settings.ClientCert}); err == nil {
+		t.Error("只设置客户端证书而没有私钥时应报错")
+	}
+}
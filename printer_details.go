@@ -0,0 +1,212 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
+)
+
+// PrinterCapabilities 打印机功能
+type PrinterCapabilities struct {
+	Color    bool `json:"color"`    // 彩色
+	Duplex   bool `json:"duplex"`   // 双面
+	A3       bool `json:"a3"`       // A3 幅面
+	Stapling bool `json:"stapling"` // 装订
+}
+
+// Labels 返回已具备功能的名称（当前界面语言）
+func (c PrinterCapabilities) Labels() []string {
+	labels := make([]string, 0, 4)
+	if c.Color {
+		labels = append(labels, tr("capability.color"))
+	}
+	if c.Duplex {
+		labels = append(labels, tr("capability.duplex"))
+	}
+	if c.A3 {
+		labels = append(labels, tr("capability.a3"))
+	}
+	if c.Stapling {
+		labels = append(labels, tr("capability.stapling"))
+	}
+	return labels
+}
+
+// Keywords 返回用于搜索的功能关键字（中英文）
+func (c PrinterCapabilities) Keywords() []string {
+	// 不随界面语言变化，两种语言的关键字都能搜到
+	keywords := make([]string, 0, 8)
+	if c.Color {
+		keywords = append(keywords, "彩色", "color", "colour")
+	}
+	if c.Duplex {
+		keywords = append(keywords, "双面", "duplex")
+	}
+	if c.A3 {
+		keywords = append(keywords, "A3")
+	}
+	if c.Stapling {
+		keywords = append(keywords, "装订", "staple", "stapling")
+	}
+	return keywords
+}
+
+// placeText 返回楼层和房间，如 "4楼 402室"
+func (p Printer) placeText() string {
+	parts := make([]string, 0, 2)
+	for _, part := range []string{p.Floor, p.Room} {
+		if part = strings.TrimSpace(part); part != "" {
+			parts = append(parts, part)
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+// cupsLocation 返回写入 CUPS printer-location 的内容（地点 + 楼层/房间）
+func (p Printer) cupsLocation(location string) string {
+	if place := p.placeText(); place != "" {
+		if location == "" {
+			return place
+		}
+		return location + " " + place
+	}
+	return location
+}
+
+// displayName 返回当前界面语言下的名称
+func (p Printer) displayName() string {
+	return p.Names.text(p.Name)
+}
+
+// displayDescription 返回当前界面语言下的描述
+func (p Printer) displayDescription() string {
+	return p.Descriptions.text(strings.TrimSpace(p.Description))
+}
+
+// cupsInfo 返回写入 CUPS printer-info 的内容
+func (p Printer) cupsInfo() string {
+	info := fmt.Sprintf("%s (%s)", p.Name, p.Model)
+	if desc := strings.TrimSpace(p.Description); desc != "" {
+		info += " - " + desc
+	}
+	return info
+}
+
+// summaryText 返回列表行中显示的位置与功能摘要
+func (p Printer) summaryText() string {
+	parts := make([]string, 0, 2)
+	if place := p.placeText(); place != "" {
+		parts = append(parts, "🏢 "+place)
+	}
+	if labels := p.Capabilities.Labels(); len(labels) > 0 {
+		parts = append(parts, strings.Join(labels, " · "))
+	}
+	return strings.Join(parts, "  ")
+}
+
+// printerImages 打印机照片缓存（URL → 资源）
+var printerImages = struct {
+	sync.Mutex
+	cache map[string]fyne.Resource
+}{cache: make(map[string]fyne.Resource)}
+
+// loadPrinterImage 下载打印机照片（带缓存）
+func loadPrinterImage(client *http.Client, imageURL string) (fyne.Resource, error) {
+	printerImages.Lock()
+	res, ok := printerImages.cache[imageURL]
+	printerImages.Unlock()
+	if ok {
+		return res, nil
+	}
+
+	resp, err := client.Get(imageURL)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if err := checkResponse(resp); err != nil {
+		return nil, err
+	}
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	res = fyne.NewStaticResource(imageURL, data)
+	printerImages.Lock()
+	printerImages.cache[imageURL] = res
+	printerImages.Unlock()
+	return res, nil
+}
+
+// newDetailsPlaceholder 创建未选中打印机时的详情面板内容
+func newDetailsPlaceholder() fyne.CanvasObject {
+	return container.NewCenter(widget.NewLabel(tr("details.placeholder")))
+}
+
+// showDetails 在详情面板中显示打印机的完整信息
+func (gui *PrinterInstallerGUI) showDetails(row PrinterRow) {
+	printer := row.Printer
+
+	title := widget.NewLabel(printer.displayName())
+	title.TextStyle = fyne.TextStyle{Bold: true}
+	title.Wrapping = fyne.TextWrapWord
+
+	form := widget.NewForm()
+	addField := func(label, value string) {
+		if value = strings.TrimSpace(value); value == "" {
+			return
+		}
+		valueLabel := widget.NewLabel(value)
+		valueLabel.Wrapping = fyne.TextWrapWord
+		form.Append(label, valueLabel)
+	}
+	addField(tr("details.queue"), printer.queueName())
+	addField(tr("details.model"), printer.Model)
+	addField(tr("details.ip"), printer.IP)
+	addField(tr("details.uri"), printer.URI)
+	addField(tr("details.location"), gui.config.locationLabel(row.Location))
+	addField(tr("details.floor"), printer.Floor)
+	addField(tr("details.room"), printer.Room)
+	addField(tr("details.capabilities"), strings.Join(printer.Capabilities.Labels(), tr("list.separator")))
+	addField(tr("details.description"), printer.displayDescription())
+	addField(tr("details.tags"), strings.Join(printer.Tags, tr("list.separator")))
+	addField(tr("details.contact"), printer.Contact)
+	addField(tr("details.source"), printer.Source)
+
+	content := container.NewVBox(title, widget.NewSeparator(), form)
+
+	if printer.ImageURL != "" {
+		imageBox := container.NewStack(widget.NewLabel(tr("details.image_loading")))
+		content.Add(imageBox)
+
+		go func(imageURL string) {
+			client, err := gui.client()
+			var res fyne.Resource
+			if err == nil {
+				res, err = loadPrinterImage(client, imageURL)
+			}
+			if err != nil {
+				imageBox.Objects = []fyne.CanvasObject{widget.NewLabel(tr("details.image_failed", err))}
+				imageBox.Refresh()
+				return
+			}
+			img := canvas.NewImageFromResource(res)
+			img.FillMode = canvas.ImageFillContain
+			img.SetMinSize(fyne.NewSize(220, 160))
+			imageBox.Objects = []fyne.CanvasObject{img}
+			imageBox.Refresh()
+		}(printer.ImageURL)
+	}
+
+	gui.detailsPanel.Objects = []fyne.CanvasObject{container.NewVScroll(content)}
+	gui.detailsPanel.Refresh()
+}
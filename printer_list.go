@@ -0,0 +1,241 @@
+package main
+
+import (
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/driver/desktop"
+	"fyne.io/fyne/v2/widget"
+)
+
+// printerList 打印机列表，在 widget.List 的键盘操作上增加：
+// ↑/↓ 移动焦点行（并在状态栏读出该行），空格勾选/取消焦点行，回车显示详情，F5 刷新配置
+type printerList struct {
+	widget.List
+
+	// focus 与 widget.List 内部的焦点行保持一致（其字段未导出），
+	// 按键处理与 List.TypedKey 相同，点击行时由 OnSelected 同步
+	focus widget.ListItemID
+
+	onToggle  func(id widget.ListItemID) // 空格
+	onFocus   func(id widget.ListItemID) // 焦点行变化
+	onRefresh func()                     // F5
+}
+
+// newPrinterList 创建打印机列表
+func newPrinterList(length func() int, createItem func() fyne.CanvasObject,
+	updateItem func(widget.ListItemID, fyne.CanvasObject)) *printerList {
+	l := &printerList{}
+	l.Length = length
+	l.CreateItem = createItem
+	l.UpdateItem = updateItem
+	l.ExtendBaseWidget(l)
+	return l
+}
+
+// setFocus 点击选中某行后同步焦点行
+func (l *printerList) setFocus(id widget.ListItemID) {
+	l.focus = id
+}
+
+// resetFocus 列表内容整体替换后把焦点行移回第一行并滚动到顶部
+// widget.List 的焦点行无法直接设置，按 ↑ 逐行移回，保持与 focus 一致
+func (l *printerList) resetFocus() {
+	up := &fyne.KeyEvent{Name: fyne.KeyUp}
+	for ; l.focus > 0; l.focus-- {
+		l.List.TypedKey(up)
+	}
+	l.ScrollToTop()
+}
+
+// TypedKey 处理列表获得焦点时的按键
+func (l *printerList) TypedKey(event *fyne.KeyEvent) {
+	length := 0
+	if l.Length != nil {
+		length = l.Length()
+	}
+
+	switch event.Name {
+	case fyne.KeySpace:
+		// 不调用 List.TypedKey（它会选中该行），空格只切换勾选
+		if l.onToggle != nil && l.focus >= 0 && l.focus < length {
+			l.onToggle(l.focus)
+		}
+		return
+	case fyne.KeyReturn, fyne.KeyEnter:
+		l.Select(l.focus)
+		return
+	case fyne.KeyF5:
+		if l.onRefresh != nil {
+			l.onRefresh()
+		}
+		return
+	case fyne.KeyDown:
+		if l.focus < length-1 {
+			l.focus++
+		}
+	case fyne.KeyUp:
+		if l.focus > 0 {
+			l.focus--
+		}
+	}
+
+	l.List.TypedKey(event)
+	if (event.Name == fyne.KeyDown || event.Name == fyne.KeyUp) && l.onFocus != nil && l.focus < length {
+		l.onFocus(l.focus)
+	}
+}
+
+// searchEntry 搜索框
+// Fyne 把快捷键交给获得焦点的控件而不是窗口，搜索框获得焦点时窗口快捷键不会触发，
+// 所以 Ctrl+I 等快捷键在这里再处理一次；↓ 和回车把焦点移到列表，F5 刷新配置
+type searchEntry struct {
+	widget.Entry
+
+	shortcuts map[string]func() // 按 ShortcutName 查找
+	onLeave   func()            // ↓、回车
+	onRefresh func()            // F5
+}
+
+// newSearchEntry 创建搜索框
+func newSearchEntry() *searchEntry {
+	e := &searchEntry{shortcuts: make(map[string]func())}
+	e.ExtendBaseWidget(e)
+	return e
+}
+
+// TypedShortcut 先处理窗口快捷键，其余（复制、粘贴、全选文字等）交给输入框
+func (e *searchEntry) TypedShortcut(shortcut fyne.Shortcut) {
+	if handler, ok := e.shortcuts[shortcut.ShortcutName()]; ok {
+		handler()
+		return
+	}
+	e.Entry.TypedShortcut(shortcut)
+}
+
+// TypedKey 处理搜索框获得焦点时的按键
+func (e *searchEntry) TypedKey(event *fyne.KeyEvent) {
+	switch event.Name {
+	case fyne.KeyDown, fyne.KeyReturn, fyne.KeyEnter:
+		if e.onLeave != nil {
+			e.onLeave()
+			return
+		}
+	case fyne.KeyF5:
+		if e.onRefresh != nil {
+			e.onRefresh()
+			return
+		}
+	}
+	e.Entry.TypedKey(event)
+}
+
+// setChecked 勾选或取消一台打印机
+func (gui *PrinterInstallerGUI) setChecked(key string, checked bool) {
+	gui.mutex.Lock()
+	gui.checkedItems[key] = checked
+	gui.mutex.Unlock()
+	gui.updateInstallBtnState()
+}
+
+// toggleRow 切换列表中一行的勾选状态（键盘空格）
+func (gui *PrinterInstallerGUI) toggleRow(id widget.ListItemID) {
+	gui.mutex.Lock()
+	if id >= len(gui.printerData) {
+		gui.mutex.Unlock()
+		return
+	}
+	row := gui.printerData[id]
+	key := printerKey(row.Location, row.Printer)
+	checked := !gui.checkedItems[key]
+	gui.mutex.Unlock()
+
+	gui.setChecked(key, checked)
+	gui.printerTable.RefreshItem(id)
+	gui.announceRow(id)
+}
+
+// announceRow 在状态栏显示焦点行的名称、型号、IP 和勾选状态
+// Fyne 没有无障碍接口，键盘用户通过状态栏确认当前所在的行
+func (gui *PrinterInstallerGUI) announceRow(id widget.ListItemID) {
+	gui.mutex.Lock()
+	if id >= len(gui.printerData) {
+		gui.mutex.Unlock()
+		return
+	}
+	row := gui.printerData[id]
+	checked := gui.checkedItems[printerKey(row.Location, row.Printer)]
+	gui.mutex.Unlock()
+
+	gui.statusText.Set(rowDescription(row, checked))
+}
+
+// rowDescription 打印机行的文字描述，如 "HP One，型号 M479，IP 10.1.2.3，已勾选"
+func rowDescription(row PrinterRow, checked bool) string {
+	state := tr("a11y.unchecked")
+	if checked {
+		state = tr("a11y.checked")
+	}
+	return tr("a11y.row", row.Printer.displayName(), row.Printer.Model, row.Printer.IP, state)
+}
+
+// addShortcuts 注册窗口快捷键
+// Ctrl+A 全选、Ctrl+Shift+A 全不选、Ctrl+I 安装、Ctrl+R/F5 刷新配置、Ctrl+F 搜索
+// 搜索框获得焦点时 Ctrl+A 全选搜索文字，其余快捷键照常生效
+func (gui *PrinterInstallerGUI) addShortcuts() {
+	c := gui.window.Canvas()
+
+	refresh := func() {
+		if !gui.refreshBtn.Disabled() {
+			go gui.loadConfig()
+		}
+	}
+	install := func() {
+		if !gui.installBtn.Disabled() {
+			gui.installPrinters()
+		}
+	}
+
+	c.AddShortcut(&fyne.ShortcutSelectAll{}, func(fyne.Shortcut) {
+		gui.selectAll()
+	})
+	c.AddShortcut(&desktop.CustomShortcut{KeyName: fyne.KeyF, Modifier: fyne.KeyModifierShortcutDefault}, func(fyne.Shortcut) {
+		c.Focus(gui.searchEntry)
+	})
+
+	// 窗口和搜索框都要处理的快捷键
+	shared := []struct {
+		shortcut fyne.Shortcut
+		handler  func()
+	}{
+		{&desktop.CustomShortcut{KeyName: fyne.KeyA, Modifier: fyne.KeyModifierShortcutDefault | fyne.KeyModifierShift}, gui.deselectAll},
+		{&desktop.CustomShortcut{KeyName: fyne.KeyI, Modifier: fyne.KeyModifierShortcutDefault}, install},
+		{&desktop.CustomShortcut{KeyName: fyne.KeyR, Modifier: fyne.KeyModifierShortcutDefault}, refresh},
+	}
+	for _, s := range shared {
+		handler := s.handler
+		c.AddShortcut(s.shortcut, func(fyne.Shortcut) {
+			handler()
+		})
+		gui.searchEntry.shortcuts[s.shortcut.ShortcutName()] = handler
+	}
+
+	// 没有控件获得焦点时的按键
+	c.SetOnTypedKey(func(event *fyne.KeyEvent) {
+		switch event.Name {
+		case fyne.KeyF5:
+			refresh()
+		case fyne.KeyDown, fyne.KeyUp:
+			c.Focus(gui.printerTable)
+		}
+	})
+
+	// 在搜索框中按 ↓ 或回车进入列表，并读出焦点行
+	gui.searchEntry.onLeave = func() {
+		c.Focus(gui.printerTable)
+		gui.announceRow(gui.printerTable.focus)
+	}
+	gui.searchEntry.onRefresh = refresh
+
+	gui.printerTable.onToggle = gui.toggleRow
+	gui.printerTable.onFocus = gui.announceRow
+	gui.printerTable.onRefresh = refresh
+}
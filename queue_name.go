@@ -0,0 +1,176 @@
+package main
+
+import (
+	"fmt"
+	"hash/fnv"
+	"strconv"
+	"strings"
+)
+
+// CUPS 队列名称
+//
+// 配置中的 queue_name 原样使用；未填写时由名称生成只含 ASCII 的名称：
+// 保留字母、数字和 - _ .，其余字符（空格、中文等）替换为 -。名称中没有可用字符时
+// （如纯中文名称）改用型号和 IP，如 "HP-M479fdw-10.1.2.30"。
+// 重名时追加由打印机自身数据决定的后缀（IP，没有 IP 时为地点和名称的短哈希），
+// 不受其他打印机的顺序影响；但新增一台同名打印机时，原来不带后缀的名称也会加上后缀，
+// 需要长期稳定的队列名称时应在配置中填写 queue_name。
+//
+// 早期版本直接以打印机名称作为队列名称，重新安装时会删除指向同一设备的旧队列（见 removeLegacyQueue）。
+
+// queueName 返回打印机的 CUPS 队列名称
+func (p Printer) queueName() string {
+	if name := strings.TrimSpace(p.QueueName); name != "" {
+		return name
+	}
+	if p.queue != "" {
+		return p.queue
+	}
+	return queueSlug(p)
+}
+
+// assignQueueNames 为未填写 queue_name 的打印机生成队列名称，并检查重名
+// CUPS 队列名称不区分大小写；手动填写的名称优先，自动生成的名称避开它们；
+// 手动填写的名称重复时记入 queueConflict，由 validatePrinter 拒绝
+func (c *PrinterConfig) assignQueueNames() {
+	used := make(map[string]string) // 小写队列名称 → 打印机（用于冲突提示）
+
+	// 手动填写的名称重复时，后出现的打印机标记为无效（安装时拒绝），避免替换前一台的队列
+	locations := sortedLocations(c)
+	for _, location := range locations {
+		printers := c.Locations[location]
+		for i := range printers {
+			name := strings.TrimSpace(printers[i].QueueName)
+			if name == "" {
+				continue
+			}
+			if other, ok := used[strings.ToLower(name)]; ok {
+				printers[i].queueConflict = tr("error.queue_duplicate", name, other)
+				continue
+			}
+			used[strings.ToLower(name)] = location + " / " + printers[i].Name
+		}
+	}
+
+	// 自动生成的名称中，多台打印机共用的名称都要加后缀
+	bases := make(map[string]int)
+	for _, location := range locations {
+		for _, printer := range c.Locations[location] {
+			if strings.TrimSpace(printer.QueueName) == "" {
+				bases[strings.ToLower(queueSlug(printer))]++
+			}
+		}
+	}
+
+	for _, location := range locations {
+		printers := c.Locations[location]
+		for i := range printers {
+			if strings.TrimSpace(printers[i].QueueName) != "" {
+				continue
+			}
+			base := queueSlug(printers[i])
+			name := base
+			if bases[strings.ToLower(base)] > 1 || used[strings.ToLower(base)] != "" {
+				name = withSuffix(base, queueSuffix(location, printers[i]))
+			}
+			if used[strings.ToLower(name)] != "" {
+				// 同一 IP 上的多台打印机（或名称本身已含 IP）
+				name = withSuffix(base, hashSuffix(location, printers[i]))
+			}
+			// 哈希也相同的极端情况下按顺序编号，保证名称不重复
+			for n := 2; used[strings.ToLower(name)] != ""; n++ {
+				name = withSuffix(base, hashSuffix(location, printers[i])+"-"+strconv.Itoa(n))
+			}
+			used[strings.ToLower(name)] = location + " / " + printers[i].Name
+			printers[i].queue = name
+		}
+	}
+}
+
+// queueSuffix 重名时的后缀：优先使用 IP，没有 IP 时使用哈希
+func queueSuffix(location string, p Printer) string {
+	if ip := slugify(p.IP); ip != "" {
+		return ip
+	}
+	return hashSuffix(location, p)
+}
+
+// hashSuffix 由地点和打印机名称（合并后的配置中唯一）计算的 8 位十六进制后缀
+func hashSuffix(location string, p Printer) string {
+	h := fnv.New32a()
+	h.Write([]byte(printerKey(location, p)))
+	return fmt.Sprintf("%08x", h.Sum32())
+}
+
+// withSuffix 追加后缀，必要时截断 base 以满足长度限制
+func withSuffix(base, suffix string) string {
+	suffix = "-" + suffix
+	return truncateASCII(base, maxQueueNameLen-len(suffix)) + suffix
+}
+
+// removeLegacyQueue 删除早期版本以打印机名称创建的旧队列
+// 只删除设备 URI 与新队列相同的队列，避免误删用户自己添加的同名打印机；失败时只记录日志
+func removeLegacyQueue(backend printerBackend, p Printer, q printerQueue) {
+	legacy := p.Name
+	if strings.EqualFold(legacy, q.Name) || validateQueueName(legacy) != nil {
+		return
+	}
+	uri, ok := queueDeviceURI(legacy)
+	if !ok || uri != q.URI {
+		return
+	}
+	if err := backend.DeletePrinter(legacy); err != nil {
+		fmt.Printf("⚠ 删除旧打印队列 %s 失败: %v\n", legacy, err)
+		return
+	}
+	fmt.Printf("已删除旧打印队列 %s（现为 %s）\n", legacy, q.Name)
+}
+
+// queueSlug 由打印机名称生成队列名称（不处理重名）
+func queueSlug(p Printer) string {
+	if slug := slugify(p.Name); slug != "" {
+		return truncateASCII(slug, maxQueueNameLen)
+	}
+	parts := make([]string, 0, 2)
+	for _, value := range []string{p.Model, p.IP} {
+		if slug := slugify(value); slug != "" {
+			parts = append(parts, slug)
+		}
+	}
+	if len(parts) == 0 {
+		return "printer"
+	}
+	return truncateASCII(strings.Join(parts, "-"), maxQueueNameLen)
+}
+
+// slugify 保留 ASCII 字母、数字和 - _ .，其余连续字符替换为一个 -，并去掉首尾的 - 和 .
+func slugify(value string) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range value {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '_':
+			b.WriteRune(r)
+			dash = false
+		case r == '-' || r == '.':
+			if !dash {
+				b.WriteRune(r)
+			}
+			dash = r == '-'
+		default:
+			if !dash && b.Len() > 0 {
+				b.WriteByte('-')
+				dash = true
+			}
+		}
+	}
+	return strings.Trim(b.String(), "-.")
+}
+
+// truncateASCII 截断 ASCII 字符串到 n 字节，并去掉末尾的 - 和 .
+func truncateASCII(value string, n int) string {
+	if len(value) > n {
+		value = value[:n]
+	}
+	return strings.TrimRight(value, "-.")
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"sort"
+	"strings"
+)
+
+// printerKey 打印机唯一标识（地点 + 名称），用于记录勾选状态
+func printerKey(location string, printer Printer) string {
+	return location + "\x00" + printer.Name
+}
+
+// sortedLocations 返回按名称排序的全部地点
+func sortedLocations(config *PrinterConfig) []string {
+	locations := make([]string, 0, len(config.Locations))
+	for location := range config.Locations {
+		locations = append(locations, location)
+	}
+	sort.Strings(locations)
+	return locations
+}
+
+// locationRows 返回指定地点的打印机行
+func locationRows(config *PrinterConfig, location string) []PrinterRow {
+	if config == nil {
+		return nil
+	}
+	printers := config.Locations[location]
+	rows := make([]PrinterRow, 0, len(printers))
+	for _, printer := range printers {
+		rows = append(rows, PrinterRow{Location: location, Printer: printer})
+	}
+	return rows
+}
+
+// searchPrinters 在所有地点中搜索打印机
+// 关键字按空白拆分，每个关键字都需命中名称、型号、IP、描述、标签、楼层/房间、功能或地点之一（不区分大小写）
+func searchPrinters(config *PrinterConfig, query string) []PrinterRow {
+	terms := strings.Fields(strings.ToLower(query))
+	if config == nil || len(terms) == 0 {
+		return nil
+	}
+
+	rows := make([]PrinterRow, 0)
+	for _, location := range sortedLocations(config) {
+		for _, row := range locationRows(config, location) {
+			if rowMatches(row, config.locationLabel(location), terms) {
+				rows = append(rows, row)
+			}
+		}
+	}
+	return rows
+}
+
+// rowMatches 判断打印机行是否命中全部关键字，名称和描述的各语言翻译都参与匹配
+func rowMatches(row PrinterRow, locationLabel string, terms []string) bool {
+	fields := []string{
+		row.Printer.Name,
+		row.Printer.Model,
+		row.Printer.IP,
+		row.Printer.Description,
+		row.Printer.Floor,
+		row.Printer.Room,
+		row.Printer.Contact,
+		row.Location,
+		locationLabel,
+	}
+	fields = append(fields, row.Printer.Names.values()...)
+	fields = append(fields, row.Printer.Descriptions.values()...)
+	fields = append(fields, row.Printer.Tags...)
+	fields = append(fields, row.Printer.Capabilities.Keywords()...)
+	haystack := strings.ToLower(strings.Join(fields, "\n"))
+
+	for _, term := range terms {
+		if !strings.Contains(haystack, term) {
+			return false
+		}
+	}
+	return true
+}
+
+// checkedRows 按地点名称顺序收集所有已勾选的打印机
+func checkedRows(config *PrinterConfig, checked map[string]bool) []PrinterRow {
+	if config == nil {
+		return nil
+	}
+
+	rows := make([]PrinterRow, 0)
+	for _, location := range sortedLocations(config) {
+		for _, row := range locationRows(config, location) {
+			if checked[printerKey(location, row.Printer)] {
+				rows = append(rows, row)
+			}
+		}
+	}
+	return rows
+}
@@ -0,0 +1,245 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// proxyModeLabels 代理模式在界面上的显示名称（翻译消息 ID）
+var proxyModeLabels = []struct {
+	mode  string
+	label string
+}{
+	{proxyModeSystem, "settings.proxy_system"},
+	{proxyModeManual, "settings.proxy_manual"},
+	{proxyModeNone, "settings.proxy_none"},
+}
+
+// themeVariantLabels 主题外观在界面上的显示名称（翻译消息 ID）
+var themeVariantLabels = []struct {
+	variant string
+	label   string
+}{
+	{themeLight, "settings.theme_light"},
+	{themeDark, "settings.theme_dark"},
+	{themeSystem, "settings.theme_system"},
+	{themeHighContrast, "settings.theme_high_contrast"},
+}
+
+// showSettings 显示设置对话框：网络（配置来源、代理、证书）和外观（主题、文字大小、语言）
+// 外观修改即时预览，保存后写入 Preferences，取消则恢复原来的外观
+func (gui *PrinterInstallerGUI) showSettings() {
+	prefs := gui.app.Preferences()
+	current := loadNetworkSettings(prefs)
+
+	labels := make([]string, 0, len(proxyModeLabels))
+	selectedLabel := tr(proxyModeLabels[0].label)
+	for _, item := range proxyModeLabels {
+		labels = append(labels, tr(item.label))
+		if item.mode == current.ProxyMode {
+			selectedLabel = tr(item.label)
+		}
+	}
+
+	sourcesEntry := widget.NewMultiLineEntry()
+	sourcesEntry.SetPlaceHolder(defaultConfigURL)
+	sourcesEntry.SetText(strings.Join(gui.configSources, "\n"))
+	sourcesEntry.SetMinRowsVisible(3)
+
+	reloadLabels := make([]string, 0, len(reloadIntervalLabels))
+	for _, item := range reloadIntervalLabels {
+		reloadLabels = append(reloadLabels, tr(item.label))
+	}
+	reloadSelect := widget.NewSelect(reloadLabels, nil)
+	reloadSelect.SetSelected(tr(reloadIntervalLabels[0].label))
+	currentInterval := loadReloadInterval(prefs)
+	for _, item := range reloadIntervalLabels {
+		if item.interval == currentInterval {
+			reloadSelect.SetSelected(tr(item.label))
+		}
+	}
+
+	proxyURLEntry := widget.NewEntry()
+	proxyURLEntry.SetPlaceHolder("http://proxy.example.com:3128")
+	proxyURLEntry.SetText(current.ProxyURL)
+
+	proxyModeSelect := widget.NewSelect(labels, func(label string) {
+		if label == tr(proxyModeLabels[1].label) {
+			proxyURLEntry.Enable()
+		} else {
+			proxyURLEntry.Disable()
+		}
+	})
+	proxyModeSelect.SetSelected(selectedLabel)
+
+	noProxyEntry := widget.NewEntry()
+	noProxyEntry.SetPlaceHolder("10.0.0.0/8,.printer.local")
+	noProxyEntry.SetText(current.NoProxy)
+
+	caEntry := widget.NewEntry()
+	caEntry.SetPlaceHolder("/etc/ssl/certs/company-ca.pem")
+	caEntry.SetText(current.CAFile)
+
+	certEntry := widget.NewEntry()
+	certEntry.SetText(current.ClientCert)
+
+	keyEntry := widget.NewEntry()
+	keyEntry.SetText(current.ClientKey)
+
+	pinEntry := widget.NewEntry()
+	pinEntry.SetPlaceHolder(tr("settings.pin_placeholder"))
+	pinEntry.SetText(current.PinnedSHA256)
+
+	items := []*widget.FormItem{
+		widget.NewFormItem(tr("settings.config_sources"), sourcesEntry),
+		widget.NewFormItem(tr("settings.reload"), reloadSelect),
+		widget.NewFormItem(tr("settings.proxy_mode"), proxyModeSelect),
+		widget.NewFormItem(tr("settings.proxy_url"), proxyURLEntry),
+		widget.NewFormItem(tr("settings.no_proxy"), noProxyEntry),
+		widget.NewFormItem(tr("settings.ca_file"), caEntry),
+		widget.NewFormItem(tr("settings.client_cert"), certEntry),
+		widget.NewFormItem(tr("settings.client_key"), keyEntry),
+		widget.NewFormItem(tr("settings.pin"), pinEntry),
+	}
+	items[0].HintText = tr("settings.config_sources_hint")
+	items[1].HintText = tr("settings.reload_hint")
+	items[4].HintText = tr("settings.no_proxy_hint")
+
+	// 外观
+	originalTheme := gui.theme.settings
+	preview := originalTheme
+
+	variantLabels := make([]string, 0, len(themeVariantLabels))
+	for _, item := range themeVariantLabels {
+		variantLabels = append(variantLabels, tr(item.label))
+	}
+	variantRadio := widget.NewRadioGroup(variantLabels, nil)
+	for _, item := range themeVariantLabels {
+		if item.variant == preview.Variant {
+			variantRadio.SetSelected(tr(item.label))
+		}
+	}
+	variantRadio.OnChanged = func(label string) {
+		for _, item := range themeVariantLabels {
+			if tr(item.label) == label {
+				preview.Variant = item.variant
+				gui.applyThemeSettings(preview)
+			}
+		}
+	}
+
+	scaleLabel := widget.NewLabel(fmt.Sprintf("%.0f%%", preview.TextScale*100))
+	scaleSlider := widget.NewSlider(minTextScale, maxTextScale)
+	scaleSlider.Step = 0.1
+	scaleSlider.SetValue(float64(preview.TextScale))
+	scaleSlider.OnChangeEnded = func(value float64) {
+		preview.TextScale = float32(value)
+		scaleLabel.SetText(fmt.Sprintf("%.0f%%", value*100))
+		gui.applyThemeSettings(preview)
+	}
+
+	// 界面语言：第一项为跟随系统，其余显示名称使用该语言本身
+	languageLabels := []string{tr("settings.language_auto")}
+	for _, item := range availableLocales {
+		languageLabels = append(languageLabels, item.label)
+	}
+	languageSelect := widget.NewSelect(languageLabels, nil)
+	languageSelect.SetSelected(languageLabels[0])
+	currentLanguage := matchLocale(prefs.String(prefLanguage))
+	for _, item := range availableLocales {
+		if item.locale == currentLanguage {
+			languageSelect.SetSelected(item.label)
+		}
+	}
+
+	appearanceForm := widget.NewForm(
+		widget.NewFormItem(tr("settings.theme"), variantRadio),
+		widget.NewFormItem(tr("settings.text_scale"), container.NewBorder(nil, nil, nil, scaleLabel, scaleSlider)),
+		widget.NewFormItem(tr("settings.language"), languageSelect),
+	)
+	appearanceForm.Items[2].HintText = tr("settings.language_hint")
+
+	tabs := container.NewAppTabs(
+		container.NewTabItemWithIcon(tr("settings.tab_network"), theme.SettingsIcon(), container.NewVScroll(widget.NewForm(items...))),
+		container.NewTabItemWithIcon(tr("settings.tab_appearance"), theme.ColorPaletteIcon(), appearanceForm),
+	)
+
+	settingsDialog := dialog.NewCustomConfirm(tr("settings.title"), tr("button.save"), tr("button.cancel"), tabs, func(confirmed bool) {
+		if !confirmed {
+			gui.applyThemeSettings(originalTheme)
+			return
+		}
+		preview.save(prefs)
+
+		language := ""
+		for _, item := range availableLocales {
+			if item.label == languageSelect.Selected {
+				language = item.locale
+			}
+		}
+		prefs.SetString(prefLanguage, language)
+
+		settings := NetworkSettings{
+			CAFile:       strings.TrimSpace(caEntry.Text),
+			ClientCert:   strings.TrimSpace(certEntry.Text),
+			ClientKey:    strings.TrimSpace(keyEntry.Text),
+			PinnedSHA256: strings.TrimSpace(pinEntry.Text),
+			ProxyURL:     strings.TrimSpace(proxyURLEntry.Text),
+			NoProxy:      strings.TrimSpace(noProxyEntry.Text),
+		}
+		for _, item := range proxyModeLabels {
+			if tr(item.label) == proxyModeSelect.Selected {
+				settings.ProxyMode = item.mode
+			}
+		}
+
+		// 先校验，有误时不保存网络设置
+		client, err := newHTTPClient(settings)
+		if err != nil {
+			dialog.ShowError(fmt.Errorf(tr("settings.network_invalid"), err), gui.window)
+			return
+		}
+
+		settings.save(prefs)
+		gui.mutex.Lock()
+		gui.httpClient, gui.httpClientErr = client, nil
+		gui.mutex.Unlock()
+		gui.statusText.Set(tr("settings.saved"))
+
+		for _, item := range reloadIntervalLabels {
+			if tr(item.label) == reloadSelect.Selected {
+				prefs.SetInt(prefReloadInterval, int(item.interval/time.Second))
+			}
+		}
+
+		// 配置来源变化时重新加载
+		prefs.SetString(prefConfigSources, strings.TrimSpace(sourcesEntry.Text))
+		sources := loadConfigSourceList(prefs)
+		if strings.Join(sources, "\n") != strings.Join(gui.configSources, "\n") {
+			gui.configSources = sources
+			go gui.loadConfig()
+		}
+	}, gui.window)
+
+	settingsDialog.Resize(fyne.NewSize(600, 600))
+	settingsDialog.Show()
+}
+
+// applyThemeSettings 立即应用外观设置（不保存）
+func (gui *PrinterInstallerGUI) applyThemeSettings(s ThemeSettings) {
+	gui.theme = gui.theme.withSettings(s)
+	gui.app.Settings().SetTheme(gui.theme)
+
+	// 标题 canvas.Text 的字号不随主题自动变化，列表行高需按新字号重新计算
+	gui.titleText.TextSize = 2 * theme.TextSize()
+	gui.titleText.Refresh()
+	gui.updateRowHeights()
+	gui.printerTable.Refresh()
+}
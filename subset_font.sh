@@ -0,0 +1,44 @@
+#!/bin/bash
+# 生成内置备用字体子集 fonts/ui-subset.otf
+# 需要 fonttools (pip install fonttools) 和 download_font.sh 下载的 NotoSansSC-Regular.otf
+set -euo pipefail
+
+SRC_FONT=${1:-NotoSansSC-Regular.otf}
+OUT_FONT=fonts/ui-subset.otf
+CHARSET=$(mktemp)
+trap 'rm -f "$CHARSET"' EXIT
+
+if [ ! -f "$SRC_FONT" ]; then
+    echo "✗ 未找到源字体: $SRC_FONT（先运行 ./download_font.sh）"
+    exit 1
+fi
+if ! command -v pyftsubset >/dev/null 2>&1; then
+    echo "✗ 未找到 pyftsubset，请安装: pip install fonttools"
+    exit 1
+fi
+
+# 收集源代码和翻译目录中的全部非 ASCII 字符 + 常用字符表 + ASCII 可见字符
+python3 - "$CHARSET" <<'PY'
+import glob, sys
+chars = set(chr(c) for c in range(0x20, 0x7f))
+for path in glob.glob("*.go") + glob.glob("locales/*.json"):
+    with open(path, encoding="utf-8") as f:
+        chars.update(c for c in f.read() if ord(c) > 0x7f)
+with open("fonts/extra_chars.txt", encoding="utf-8") as f:
+    for line in f:
+        if not line.startswith("#"):
+            chars.update(c for c in line.strip() if not c.isspace())
+with open(sys.argv[1], "w", encoding="utf-8") as out:
+    out.write("".join(sorted(chars)))
+print("字符数: %d" % len(chars))
+PY
+
+pyftsubset "$SRC_FONT" \
+    --text-file="$CHARSET" \
+    --layout-features='*' \
+    --no-hinting \
+    --desubroutinize \
+    --output-file="$OUT_FONT"
+
+echo "✓ 已生成 $OUT_FONT"
+ls -lh "$OUT_FONT"
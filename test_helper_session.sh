@@ -0,0 +1,101 @@
+#!/bin/bash
+
+# 特权助手本地测试脚本
+# 在独立的会话总线上以 --session --dry-run 模式启动助手，
+# 不需要 root，也不会修改 CUPS 配置
+#
+# 用法: ./test_helper_session.sh [可执行文件路径]
+#
+# 如需联调图形界面，可在同一会话总线中运行：
+#   PRINTER_HELPER_BUS=session ./printer-installer
+
+set -euo pipefail
+
+BIN="${1:-./printer-installer}"
+
+if [ -z "${HELPER_TEST_INNER:-}" ]; then
+    if ! command -v dbus-run-session >/dev/null 2>&1; then
+        echo "✗ 需要 dbus-run-session（sudo apt-get install dbus）"
+        exit 1
+    fi
+    exec env HELPER_TEST_INNER=1 dbus-run-session -- "$0" "$BIN"
+fi
+
+BUS_NAME=com.kylin.printer.Helper
+OBJ_PATH=/com/kylin/printer/Helper
+IFACE=com.kylin.printer.Helper
+LOG=$(mktemp)
+PASS=0
+FAIL=0
+
+"$BIN" helper --session --dry-run > "$LOG" 2>&1 &
+HELPER_PID=$!
+trap 'kill $HELPER_PID 2>/dev/null || true; rm -f "$LOG"' EXIT
+
+# 等待助手注册总线名称
+for _ in $(seq 1 50); do
+    if dbus-send --session --print-reply --dest=org.freedesktop.DBus / \
+        org.freedesktop.DBus.NameHasOwner string:$BUS_NAME 2>/dev/null | grep -q "true"; then
+        break
+    fi
+    sleep 0.1
+done
+
+call() {
+    dbus-send --session --print-reply --dest=$BUS_NAME $OBJ_PATH "$@" 2>&1
+}
+
+expect_ok() {
+    local desc=$1; shift
+    if output=$(call "$@"); then
+        echo "✓ $desc"
+        PASS=$((PASS + 1))
+    else
+        echo "✗ $desc: $output"
+        FAIL=$((FAIL + 1))
+    fi
+}
+
+expect_fail() {
+    local desc=$1; shift
+    if output=$(call "$@"); then
+        echo "✗ $desc: 应当被拒绝"
+        FAIL=$((FAIL + 1))
+    else
+        echo "✓ $desc"
+        PASS=$((PASS + 1))
+    fi
+}
+
+# PPD 内容（"*PPD-Adobe" 的字节）
+PPD_BYTES="0x2a,0x50,0x50,0x44,0x2d,0x41,0x64,0x6f,0x62,0x65"
+
+expect_ok "Ping" org.freedesktop.DBus.Peer.Ping
+expect_ok "创建打印队列" $IFACE.AddPrinter \
+    string:Test-Printer string:ipp://10.0.0.1/ipp/print string:"测试打印机" string:"三楼" \
+    array:byte:$PPD_BYTES
+expect_fail "拒绝以 - 开头的队列名称" $IFACE.AddPrinter \
+    string:-x string:ipp://10.0.0.1/ipp/print string:info string:loc array:byte:$PPD_BYTES
+expect_fail "拒绝白名单以外的 URI 协议" $IFACE.AddPrinter \
+    string:Test-Printer string:file:///etc/passwd string:info string:loc array:byte:$PPD_BYTES
+expect_fail "拒绝包含空格的队列名称" $IFACE.AddPrinter \
+    string:"Test Printer" string:ipp://10.0.0.1/ipp/print string:info string:loc array:byte:$PPD_BYTES
+expect_fail "拒绝空 PPD" $IFACE.AddPrinter \
+    string:Test-Printer string:ipp://10.0.0.1/ipp/print string:info string:loc array:byte:
+expect_ok "删除打印队列" $IFACE.DeletePrinter string:Test-Printer
+expect_fail "拒绝白名单以外的方法" $IFACE.SetDefault string:Test-Printer
+
+if grep -q "lpadmin -p Test-Printer" "$LOG"; then
+    echo "✓ 助手输出了预期的 lpadmin 命令"
+    PASS=$((PASS + 1))
+else
+    echo "✗ 助手输出中没有预期的 lpadmin 命令"
+    FAIL=$((FAIL + 1))
+fi
+
+echo ""
+echo "=== 助手日志 ==="
+cat "$LOG"
+echo ""
+echo "通过: $PASS, 失败: $FAIL"
+[ $FAIL -eq 0 ]
@@ -0,0 +1,193 @@
+package main
+
+import (
+	"fmt"
+	"image/color"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/theme"
+)
+
+// 主题外观
+const (
+	themeLight        = "light"
+	themeDark         = "dark"
+	themeSystem       = "system"        // 跟随系统
+	themeHighContrast = "high_contrast" // 高对比度（白底黑字、黑色边框）
+)
+
+// Preferences 中保存外观设置的键
+const (
+	prefThemeVariant = "theme_variant"
+	prefTextScale    = "text_scale"
+)
+
+// 文字缩放范围
+const (
+	minTextScale = 0.8
+	maxTextScale = 1.6
+)
+
+// baseTextSize 缩放前的正文字号（比 Fyne 默认稍大）
+const baseTextSize = 14
+
+// 自定义颜色
+var (
+	kylinBlue   = color.RGBA{R: 40, G: 102, B: 255, A: 255}  // 麒麟蓝（所有外观下的主色）
+	lightBg     = color.RGBA{R: 248, G: 250, B: 252, A: 255} // 浅灰背景
+	darkBg      = color.RGBA{R: 15, G: 23, B: 42, A: 255}    // 深色背景
+	darkInputBg = color.RGBA{R: 30, G: 41, B: 59, A: 255}    // 深色输入框背景
+)
+
+// ThemeSettings 外观设置
+type ThemeSettings struct {
+	Variant   string  // themeLight、themeDark、themeSystem 或 themeHighContrast
+	TextScale float32 // 文字缩放比例，1 为默认大小
+}
+
+// loadThemeSettings 从 Preferences 读取外观设置
+func loadThemeSettings(prefs fyne.Preferences) ThemeSettings {
+	s := ThemeSettings{
+		Variant:   prefs.StringWithFallback(prefThemeVariant, themeLight),
+		TextScale: float32(prefs.FloatWithFallback(prefTextScale, 1)),
+	}
+	switch s.Variant {
+	case themeLight, themeDark, themeSystem, themeHighContrast:
+	default:
+		s.Variant = themeLight
+	}
+	if s.TextScale < minTextScale || s.TextScale > maxTextScale {
+		s.TextScale = 1
+	}
+	return s
+}
+
+// save 保存外观设置到 Preferences
+func (s ThemeSettings) save(prefs fyne.Preferences) {
+	prefs.SetString(prefThemeVariant, s.Variant)
+	prefs.SetFloat(prefTextScale, float64(s.TextScale))
+}
+
+// appTheme 自定义主题：中文字体、麒麟蓝主色，支持浅色、深色、跟随系统和高对比度
+type appTheme struct {
+	regular    fyne.Resource
+	bold       fyne.Resource
+	fontLogged bool        // 用于避免重复打印调试信息
+	report     *fontReport // 字体查找结果
+	settings   ThemeSettings
+}
+
+// newAppTheme 创建主题，sample 为除界面文字外还需覆盖的字符（上次加载的打印机名称等）
+func newAppTheme(sample string) *appTheme {
+	t := &appTheme{settings: ThemeSettings{Variant: themeLight, TextScale: 1}}
+	t.loadFonts(sample)
+	return t
+}
+
+// withSettings 返回使用相同字体、不同外观设置的主题
+func (m *appTheme) withSettings(s ThemeSettings) *appTheme {
+	t := *m
+	t.settings = s
+	return &t
+}
+
+// loadFonts 查找并加载中文字体，详细过程见 fontReport（diagnose fonts 命令可查看）
+func (m *appTheme) loadFonts(sample string) {
+	report, regular, bold := findFont(sample)
+	m.report = report
+	if regular != nil {
+		m.regular = fyne.NewStaticResource("regular", regular)
+		m.bold = fyne.NewStaticResource("bold", bold)
+	}
+	fmt.Println(report.Summary())
+}
+
+func (m *appTheme) Color(name fyne.ThemeColorName, variant fyne.ThemeVariant) color.Color {
+	if name == theme.ColorNamePrimary {
+		return kylinBlue
+	}
+
+	switch m.settings.Variant {
+	case themeHighContrast:
+		return highContrastColor(name)
+	case themeDark:
+		variant = theme.VariantDark
+	case themeLight:
+		variant = theme.VariantLight
+	}
+	// themeSystem 使用 Fyne 传入的系统外观
+
+	if variant == theme.VariantDark {
+		switch name {
+		case theme.ColorNameBackground:
+			return darkBg
+		case theme.ColorNameInputBackground:
+			return darkInputBg
+		}
+	} else {
+		switch name {
+		case theme.ColorNameBackground:
+			return lightBg
+		case theme.ColorNameInputBackground:
+			return color.White
+		}
+	}
+	return theme.DefaultTheme().Color(name, variant)
+}
+
+// highContrastColor 高对比度配色：白底黑字，边框和分隔线为黑色
+func highContrastColor(name fyne.ThemeColorName) color.Color {
+	switch name {
+	case theme.ColorNameBackground, theme.ColorNameInputBackground, theme.ColorNameMenuBackground,
+		theme.ColorNameOverlayBackground, theme.ColorNameHeaderBackground:
+		return color.White
+	case theme.ColorNameForeground, theme.ColorNameInputBorder, theme.ColorNameSeparator:
+		return color.Black
+	case theme.ColorNamePlaceHolder, theme.ColorNameDisabled:
+		return color.RGBA{R: 68, G: 68, B: 68, A: 255}
+	case theme.ColorNameButton:
+		return color.RGBA{R: 224, G: 224, B: 224, A: 255}
+	case theme.ColorNameFocus, theme.ColorNameSelection:
+		return color.RGBA{R: 40, G: 102, B: 255, A: 96}
+	}
+	return theme.DefaultTheme().Color(name, theme.VariantLight)
+}
+
+func (m *appTheme) Icon(name fyne.ThemeIconName) fyne.Resource {
+	return theme.DefaultTheme().Icon(name)
+}
+
+func (m *appTheme) Font(style fyne.TextStyle) fyne.Resource {
+	if m.regular != nil {
+		// 只在第一次调用时打印（避免刷屏）
+		if !m.fontLogged {
+			fmt.Printf("✓ 主题字体已应用 (regular: %d bytes, bold: %d bytes)\n",
+				len(m.regular.Content()), len(m.bold.Content()))
+			m.fontLogged = true
+		}
+
+		if style.Bold {
+			return m.bold
+		}
+		return m.regular
+	}
+	return theme.DefaultTheme().Font(style)
+}
+
+func (m *appTheme) Size(name fyne.ThemeSizeName) float32 {
+	scale := m.settings.TextScale
+	if scale <= 0 {
+		scale = 1
+	}
+	switch name {
+	case theme.SizeNameText:
+		return baseTextSize * scale
+	case theme.SizeNameHeadingText, theme.SizeNameSubHeadingText, theme.SizeNameCaptionText, theme.SizeNameInlineIcon:
+		return theme.DefaultTheme().Size(name) * scale
+	case theme.SizeNameInputBorder:
+		if m.settings.Variant == themeHighContrast {
+			return 2
+		}
+	}
+	return theme.DefaultTheme().Size(name)
+}
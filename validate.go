@@ -0,0 +1,140 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// allowedURISchemes 允许的打印机设备 URI 协议
+var allowedURISchemes = map[string]bool{
+	"ipp":    true,
+	"ipps":   true,
+	"socket": true,
+	"lpd":    true,
+	"smb":    true,
+	"dnssd":  true,
+}
+
+const (
+	maxQueueNameLen = 127 // CUPS 队列名称最大长度（字节）
+	maxTextLen      = 255 // 描述、位置最大长度（字节）
+)
+
+// validateQueueName 按 CUPS 规则检查队列名称
+// 长度 1-127 字节，不能包含空白、控制字符以及 / \ ? ' " #，且不能以 - 开头
+func validateQueueName(name string) error {
+	if name == "" {
+		return errors.New(tr("error.queue_empty"))
+	}
+	if len(name) > maxQueueNameLen {
+		return fmt.Errorf(tr("error.queue_too_long"), maxQueueNameLen, name)
+	}
+	if strings.HasPrefix(name, "-") {
+		return fmt.Errorf(tr("error.queue_dash"), name)
+	}
+	for _, r := range name {
+		if r <= ' ' || r == 0x7f || unicode.IsSpace(r) || unicode.IsControl(r) {
+			return fmt.Errorf(tr("error.queue_space"), name)
+		}
+		if strings.ContainsRune(`/\?'"#`, r) {
+			return fmt.Errorf(tr("error.queue_char"), r, name)
+		}
+	}
+	return nil
+}
+
+// validateDeviceURI 检查设备 URI：协议必须在白名单内，且必须包含主机
+// 不用 url.Parse：CUPS 的 dnssd URI 把转义后的服务名放在主机部分
+// （如 dnssd://HP%20LaserJet._ipp._tcp.local/），url.Parse 会拒绝这种主机
+func validateDeviceURI(uri string) error {
+	if uri == "" {
+		return errors.New(tr("error.uri_empty"))
+	}
+	if strings.HasPrefix(uri, "-") {
+		return fmt.Errorf(tr("error.uri_dash"), uri)
+	}
+	for _, r := range uri {
+		if unicode.IsSpace(r) || unicode.IsControl(r) {
+			return fmt.Errorf(tr("error.uri_space"), uri)
+		}
+	}
+
+	scheme, rest, ok := strings.Cut(uri, "://")
+	if !ok {
+		return fmt.Errorf(tr("error.uri_format"), uri)
+	}
+	if !allowedURISchemes[strings.ToLower(scheme)] {
+		return fmt.Errorf(tr("error.uri_scheme"), scheme)
+	}
+
+	// 主机部分：到第一个 / ? # 为止，去掉 user@ 前缀
+	host := rest
+	if i := strings.IndexAny(host, "/?#"); i >= 0 {
+		host = host[:i]
+	}
+	if i := strings.LastIndex(host, "@"); i >= 0 {
+		host = host[i+1:]
+	}
+	if host == "" || strings.HasPrefix(host, "-") {
+		return fmt.Errorf(tr("error.uri_host"), uri)
+	}
+	return nil
+}
+
+// validateText 检查描述、位置等自由文本
+func validateText(field, value string) error {
+	if len(value) > maxTextLen {
+		return fmt.Errorf(tr("error.text_too_long"), field, maxTextLen)
+	}
+	if strings.HasPrefix(value, "-") {
+		return fmt.Errorf(tr("error.text_dash"), field, value)
+	}
+	for _, r := range value {
+		if unicode.IsControl(r) {
+			return fmt.Errorf(tr("error.text_control"), field, value)
+		}
+	}
+	return nil
+}
+
+// validateQueue 检查传给 lpadmin 的全部参数
+func validateQueue(q printerQueue) error {
+	if err := validateQueueName(q.Name); err != nil {
+		return err
+	}
+	if err := validateDeviceURI(q.URI); err != nil {
+		return err
+	}
+	if err := validateText(tr("error.field_description"), q.Info); err != nil {
+		return err
+	}
+	return validateText(tr("error.field_location"), q.Location)
+}
+
+// validatePrinter 检查一台打印机能否安装：队列名称不能与其他打印机重复，传给 lpadmin 的参数有效
+func validatePrinter(row PrinterRow) error {
+	if row.Printer.queueConflict != "" {
+		return errors.New(row.Printer.queueConflict)
+	}
+	return validateQueue(newPrinterQueue(row))
+}
+
+// validateConfig 检查配置中的全部打印机，返回问题列表
+// 包括合并多个来源时的冲突，以及每台参数无效的打印机
+func validateConfig(config *PrinterConfig) []string {
+	if config == nil {
+		return nil
+	}
+
+	issues := append([]string{}, config.conflicts...)
+	for _, location := range sortedLocations(config) {
+		for _, row := range locationRows(config, location) {
+			if err := validatePrinter(row); err != nil {
+				issues = append(issues, fmt.Sprintf("%s / %s: %v", location, row.Printer.Name, err))
+			}
+		}
+	}
+	return issues
+}